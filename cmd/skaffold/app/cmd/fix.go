@@ -94,6 +94,10 @@ func fix(out io.Writer, configFile, outFile string, toVersion string) error {
 	if upgraded, err = schema.UpgradeTo(versionedCfgs, toVersion); err != nil {
 		return err
 	}
+	changes, err := upgradeChangelog(versionedCfgs, upgraded)
+	if err != nil {
+		return err
+	}
 
 	// TODO(dgageot): We should be able run validations on any schema version
 	// but that's not the case. They can only run on the latest version for now.
@@ -131,6 +135,7 @@ func fix(out io.Writer, configFile, outFile string, toVersion string) error {
 			return fmt.Errorf("writing config file: %w", err)
 		}
 		output.Default.Fprintf(out, "New config at version %s generated and written to %s\n", toVersion, outFile)
+		printChangelog(out, changes)
 		if mvErr != nil {
 			output.Yellow.Fprintln(out, "Error moving old config. Dumping old v2 config on stdout:")
 			output.Default.Fprintln(out, getOldConfigYaml(versionedCfgs))
@@ -141,6 +146,39 @@ func fix(out io.Writer, configFile, outFile string, toVersion string) error {
 	return nil
 }
 
+// upgradeChangelog collects the notable field-level changes applied when
+// upgrading each config, deduplicated across configs sharing an upgrade path.
+func upgradeChangelog(olds, upgraded []util.VersionedConfig) ([]string, error) {
+	var changes []string
+	seen := map[string]bool{}
+	for i, cfg := range olds {
+		if cfg.GetVersion() == upgraded[i].GetVersion() {
+			continue
+		}
+		entries, err := schema.UpgradeChangelog(cfg.GetVersion(), upgraded[i].GetVersion())
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !seen[entry] {
+				seen[entry] = true
+				changes = append(changes, entry)
+			}
+		}
+	}
+	return changes, nil
+}
+
+func printChangelog(out io.Writer, changes []string) {
+	if len(changes) == 0 {
+		return
+	}
+	output.Default.Fprintln(out, "Notable changes applied by the upgrade:")
+	for _, change := range changes {
+		output.Default.Fprintf(out, " - %s\n", change)
+	}
+}
+
 func getOldConfigYaml(cfgs []util.VersionedConfig) string {
 	yamlStr, err := yaml.MarshalWithSeparator(cfgs)
 	if err != nil {
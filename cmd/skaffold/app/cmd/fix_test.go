@@ -214,3 +214,30 @@ deploy:
 		t.CheckDeepEqual(expectedOutput, string(output))
 	})
 }
+
+func TestFixPrintsChangelog(t *testing.T) {
+	inputYaml := `apiVersion: skaffold/v1alpha4
+kind: Config
+build:
+  artifacts:
+  - image: docker/image
+    docker:
+      dockerfile: dockerfile.test
+deploy:
+  kubectl:
+    manifests:
+    - k8s/deployment.yaml
+`
+
+	testutil.Run(t, "", func(t *testutil.T) {
+		cfgFile := t.TempFile("config", []byte(inputYaml))
+		outFile := t.TempFile("fixed", nil)
+
+		var b bytes.Buffer
+		err := fix(&b, cfgFile, outFile, latest.Version)
+
+		t.CheckNoError(err)
+		t.CheckContains("Notable changes applied by the upgrade:", b.String())
+		t.CheckContains("skaffold/v3alpha1: deploy.kubectl.manifests moved to manifests.rawYaml", b.String())
+	})
+}
@@ -20,16 +20,21 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/custom"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/misc"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	kubernetesclient "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/client"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/output/log"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/platform"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 )
 
 // Build builds a list of artifacts with Kaniko.
@@ -39,6 +44,13 @@ func (b *Builder) Build(ctx context.Context, out io.Writer, artifact *latest.Art
 }
 
 func (b *Builder) PreBuild(ctx context.Context, out io.Writer) error {
+	// the namespace check costs an API round-trip per build, so it is opt-in.
+	if os.Getenv("SKAFFOLD_CHECK_BUILD_NAMESPACE") != "" {
+		if err := b.checkNamespace(ctx); err != nil {
+			return err
+		}
+	}
+
 	teardownPullSecret, err := b.setupPullSecret(ctx, out)
 	if err != nil {
 		return fmt.Errorf("setting up pull secret: %w", err)
@@ -95,6 +107,20 @@ func (b *Builder) runBuildForArtifact(ctx context.Context, out io.Writer, a *lat
 	}
 }
 
+// checkNamespace verifies that the configured build namespace exists in the
+// cluster, warning when it does not since the build pod cannot be scheduled
+// until the namespace is created.
+func (b *Builder) checkNamespace(ctx context.Context) error {
+	client, err := kubernetesclient.DefaultClient()
+	if err != nil {
+		return fmt.Errorf("getting Kubernetes client: %w", err)
+	}
+	if _, err := client.CoreV1().Namespaces().Get(ctx, b.Namespace, metav1.GetOptions{}); err != nil {
+		warnings.Printf("build namespace %q not found: it will need to be created before the build can run", b.Namespace)
+	}
+	return nil
+}
+
 func (b *Builder) retrieveExtraEnv() []string {
 	env := []string{
 		fmt.Sprintf("%s=%s", constants.KubeContext, b.cfg.GetKubeContext()),
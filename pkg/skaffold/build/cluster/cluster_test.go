@@ -17,12 +17,60 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/client"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
+func TestCheckNamespace(t *testing.T) {
+	tests := []struct {
+		description      string
+		namespaces       []string
+		expectedWarnings []string
+	}{
+		{
+			description: "existing namespace produces no warning",
+			namespaces:  []string{"nskaniko"},
+		},
+		{
+			description:      "missing namespace warns that it must be created",
+			expectedWarnings: []string{`build namespace "nskaniko" not found: it will need to be created before the build can run`},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var objects []runtime.Object
+			for _, ns := range test.namespaces {
+				objects = append(objects, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+			}
+			t.Override(&client.DefaultClient, func() (kubernetes.Interface, error) {
+				return fake.NewSimpleClientset(objects...), nil
+			})
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			builder, err := NewBuilder(&mockBuilderContext{}, &latest.ClusterDetails{
+				Timeout:   "20m",
+				Namespace: "nskaniko",
+			})
+			t.CheckNoError(err)
+
+			t.CheckNoError(builder.checkNamespace(context.Background()))
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
 func TestRetrieveEnv(t *testing.T) {
 	builder, err := NewBuilder(&mockBuilderContext{
 		kubeContext: "kubecontext",
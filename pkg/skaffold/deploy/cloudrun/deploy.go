@@ -18,32 +18,55 @@ package cloudrun
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/run/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8syaml "sigs.k8s.io/yaml"
 
+	"github.com/google/go-containerregistry/pkg/name"
+
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/access"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/config"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/debug"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/label"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	sErrors "github.com/GoogleContainerTools/skaffold/pkg/skaffold/errors"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/gcp"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/graph"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/manifest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/log"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/output"
+	olog "github.com/GoogleContainerTools/skaffold/pkg/skaffold/output/log"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/status"
-	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	pkgsync "github.com/GoogleContainerTools/skaffold/pkg/skaffold/sync"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/version"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/proto/v1"
 )
 
+// isVerbose reports whether the full service config should be echoed to the user,
+// overridable for testing.
+var isVerbose = olog.IsDebugLevelEnabled
+
 // Config contains config options needed for cloud run
 type Config interface {
 	PortForwardResources() []*latest.PortForwardResource
@@ -62,44 +85,540 @@ type Deployer struct {
 	Project string
 	Region  string
 
-	// additional client options for connecting to Cloud Run, used for tests
+	// containerDependencies is the startup order to enforce on multi-container Services.
+	containerDependencies map[string][]string
+
+	// readinessTimeout bounds the status monitor's wait for the Ready condition.
+	// Zero means the monitor's default deadline applies.
+	readinessTimeout time.Duration
+
+	// readyConditionType is the status condition the monitor waits on for
+	// Services. Empty means the Ready condition.
+	readyConditionType string
+
+	// annotationStrategy controls how configured annotations combine with the
+	// ones already in the manifest: merge keeps them, replace discards them.
+	annotationStrategy string
+
+	// pinDigest records built image digests on the deployed revision for auditing.
+	pinDigest bool
+
+	// maxRevisions caps how many revisions of a Service are retained after deploy.
+	// Zero means revisions are never pruned.
+	maxRevisions int
+
+	// changeCause describes why the deploy happened, recorded on the revision.
+	changeCause string
+	// description is a human-readable description of the service, shown in the
+	// Cloud console.
+	description string
+
+	// launchStage is the Cloud Run launch stage annotation to set on the service.
+	launchStage string
+
+	// useHTTP2 names the container port h2c to enable end-to-end HTTP/2.
+	useHTTP2 bool
+
+	// serviceAccount is the IAM service account the service's revisions run
+	// as. Empty leaves the manifest's service account in place.
+	serviceAccount string
+
+	// vpcConnector and vpcEgress route the service's outbound traffic through a
+	// Serverless VPC Access connector.
+	vpcConnector string
+	vpcEgress    string
+
+	// containerConcurrency, minInstances and maxInstances control per-revision
+	// concurrency and autoscaling. Zero leaves the Cloud Run default in place.
+	containerConcurrency int
+	minInstances         int
+	maxInstances         int
+
+	// traffic is the configured traffic split across revisions. Empty means
+	// Cloud Run routes all traffic to the latest revision.
+	traffic []latest.CloudRunTrafficTarget
+
+	// terminationGracePeriod is how long an instance may take to shut down after
+	// SIGTERM, in seconds. Zero leaves the Cloud Run default in place.
+	terminationGracePeriod int
+
+	// uploadRepo is a Container Registry or Artifact Registry repository built
+	// images are copied to when they are not already hosted in a registry Cloud
+	// Run can pull from. Empty means such images are rejected instead.
+	uploadRepo string
+	// dockerConfig supplies registry settings for the image copy. Nil when the
+	// run context does not carry docker configuration.
+	dockerConfig docker.Config
+
+	// defaultLabels are stamped on every deployed service and revision, beneath
+	// labels from the manifest and skaffold's run-id labels.
+	defaultLabels map[string]string
+
+	// strictContainerLimit turns the container-count check against the managed
+	// Cloud Run limit from a warning into a deploy failure.
+	strictContainerLimit bool
+
+	// startupProbe is applied to every container of a deployed service so Cloud
+	// Run knows when a container has started. Nil leaves the manifest probes
+	// and Cloud Run defaults in place.
+	startupProbe *latest.CloudRunStartupProbe
+
+	// templateManifests expands manifests as Go templates against the process
+	// environment before deploying them. Off by default: manifests are raw YAML.
+	templateManifests bool
+
+	// appliedServiceFile is a file the service configuration sent to Cloud Run
+	// is written to after a successful deploy. Empty means no file is written.
+	// appliedServiceMu serializes writes when several services deploy at once.
+	appliedServiceFile string
+	appliedServiceMu   sync.Mutex
+
+	// buildArtifactsURL is an HTTP(S) URL serving a build artifacts JSON whose
+	// images join the substitution alongside locally built artifacts. Empty
+	// means only locally built artifacts are substituted.
+	buildArtifactsURL string
+
+	// reportImageDiff prints, per container, the image currently deployed and
+	// the one this deploy would apply, so substitutions can be reviewed. Set
+	// with the SKAFFOLD_CLOUD_RUN_IMAGE_DIFF environment variable.
+	reportImageDiff bool
+
+	// retryAttempts, retryBaseDelay and retryMaxDelay tune how transient Cloud
+	// Run API errors are retried: up to retryAttempts tries, with exponential
+	// backoff starting at retryBaseDelay and capped at retryMaxDelay.
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+
+	// apiEndpoint overrides the base URL of the Cloud Run API, for regional
+	// endpoints or Trusted Partner Cloud deployments. Empty means the regional
+	// default endpoint.
+	apiEndpoint string
+
+	// validatePlatforms checks before deploying that built images can run on
+	// managed Cloud Run, which only runs linux/amd64 images.
+	validatePlatforms bool
+
+	// executeJob starts an execution of each deployed Job once its definition
+	// is updated. Off by default: deploying a Job only updates the definition.
+	executeJob bool
+
+	// waitForDeletion makes Cleanup poll until deleted services are fully gone.
+	waitForDeletion bool
+
+	// forceDelete disables deletion protection on services before deleting them.
+	forceDelete bool
+
+	// env, envFileVars and secretEnv hold the configured per-revision environment,
+	// in decreasing precedence: env > envFileVars > secretEnv > manifest env.
+	env         map[string]string
+	envFileVars map[string]string
+	secretEnv   map[string]string
+
+	// envFile and credentialsFile are the local files the deploy reads,
+	// watched in dev mode so changes trigger a redeploy.
+	envFile         string
+	credentialsFile string
+
+	// envPrefix namespaces the injected environment per container, keyed by
+	// container name. When set, only the named containers receive the
+	// configured environment, with the prefix applied to each variable name.
+	envPrefix map[string]string
+
+	// resourcesMu guards the accessor and monitor resource lists, which are
+	// appended to from concurrent service deploys.
+	resourcesMu sync.Mutex
+
+	// crclient is the Cloud Run API client, built on first use and shared by
+	// Deploy and Cleanup so a dev loop doesn't repeat the auth round-trip.
+	// Guarded by clientMu.
+	crclient *run.APIService
+	clientMu sync.Mutex
+
+	// additional client options for connecting to Cloud Run, set for configured
+	// credentials and used for tests
 	clientOptions []option.ClientOption
 	useGcpOptions bool
+	// userAgent identifies Skaffold on outgoing Cloud Run API requests.
+	userAgent string
+}
+
+// Option adjusts a Deployer at construction time, for settings that have no
+// place in the config schema, such as test-only client options.
+type Option func(*Deployer)
+
+// WithClientOptions appends options for the Cloud Run API client, letting
+// tests point the deployer at a fake server with a custom HTTP transport
+// instead of the authenticated GCP client.
+func WithClientOptions(opts ...option.ClientOption) Option {
+	return func(d *Deployer) {
+		d.clientOptions = append(d.clientOptions, opts...)
+	}
 }
 
 // NewDeployer creates a new Deployer for Cloud Run from the Skaffold deploy config.
-func NewDeployer(cfg Config, labeller *label.DefaultLabeller, crDeploy *latest.CloudRunDeploy, configName string) (*Deployer, error) {
-	return &Deployer{
-		configName: configName,
-		Project:    crDeploy.ProjectID,
-		Region:     crDeploy.Region,
+func NewDeployer(cfg Config, labeller *label.DefaultLabeller, crDeploy *latest.CloudRunDeploy, configName string, opts ...Option) (*Deployer, error) {
+	var readinessTimeout time.Duration
+	if crDeploy.ReadinessTimeout != "" {
+		var err error
+		readinessTimeout, err = time.ParseDuration(crDeploy.ReadinessTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse readinessTimeout %q: %w", crDeploy.ReadinessTimeout, err)
+		}
+	}
+	changeCause := crDeploy.ChangeCause
+	if changeCause == "" {
+		changeCause = headCommitMessage()
+	}
+	// resolve the region, falling back from the config (profiles are already
+	// merged into it) to the gcloud configuration to the configured default.
+	region := crDeploy.Region
+	regionSource := "skaffold config"
+	if region == "" {
+		region = gcloudRunRegion()
+		regionSource = "gcloud configuration"
+	}
+	if region == "" {
+		region = crDeploy.DefaultRegion
+		regionSource = "configured default"
+	}
+	if region != "" {
+		olog.Entry(context.TODO()).Debugf("using Cloud Run region %q from %s", region, regionSource)
+	}
+	if len(crDeploy.Description) > maxDescriptionLength {
+		return nil, fmt.Errorf("description is too long (%d characters): Cloud Run limits the service description to %d characters", len(crDeploy.Description), maxDescriptionLength)
+	}
+	switch crDeploy.LaunchStage {
+	case "", "ALPHA", "BETA", "GA":
+	default:
+		return nil, fmt.Errorf("invalid launchStage %q: must be one of ALPHA, BETA or GA", crDeploy.LaunchStage)
+	}
+	switch crDeploy.VPCEgress {
+	case "":
+	case "all-traffic", "private-ranges-only":
+		if crDeploy.VPCConnector == "" {
+			return nil, fmt.Errorf("vpcEgress %q requires a vpcConnector to route traffic through", crDeploy.VPCEgress)
+		}
+	default:
+		return nil, fmt.Errorf("invalid vpcEgress %q: must be one of all-traffic or private-ranges-only", crDeploy.VPCEgress)
+	}
+	if crDeploy.ContainerConcurrency < 0 || crDeploy.MinInstances < 0 || crDeploy.MaxInstances < 0 {
+		return nil, fmt.Errorf("containerConcurrency, minInstances and maxInstances must be non-negative")
+	}
+	if crDeploy.MaxInstances > 0 && crDeploy.MinInstances > crDeploy.MaxInstances {
+		return nil, fmt.Errorf("minInstances (%d) must not exceed maxInstances (%d)", crDeploy.MinInstances, crDeploy.MaxInstances)
+	}
+	switch crDeploy.ReadyConditionType {
+	case "", "Ready", "RoutesReady", "ConfigurationsReady":
+	default:
+		return nil, fmt.Errorf("invalid readyConditionType %q: must be one of Ready, RoutesReady or ConfigurationsReady", crDeploy.ReadyConditionType)
+	}
+	switch crDeploy.AnnotationMergeStrategy {
+	case "", annotationStrategyMerge, annotationStrategyReplace:
+	default:
+		return nil, fmt.Errorf("invalid annotationMergeStrategy %q: must be merge or replace", crDeploy.AnnotationMergeStrategy)
+	}
+	if len(crDeploy.Traffic) > 0 {
+		var total int64
+		for _, target := range crDeploy.Traffic {
+			if target.RevisionName == "" && !target.LatestRevision {
+				return nil, fmt.Errorf("traffic targets must name a revision or set latestRevision")
+			}
+			if target.RevisionName != "" && target.LatestRevision {
+				return nil, fmt.Errorf("traffic target %q cannot also set latestRevision", target.RevisionName)
+			}
+			if target.Percent < 0 || target.Percent > 100 {
+				return nil, fmt.Errorf("traffic percent (%d) must be between 0 and 100", target.Percent)
+			}
+			total += target.Percent
+		}
+		if total != 100 {
+			return nil, fmt.Errorf("traffic percents must add up to 100, got %d", total)
+		}
+	}
+	if crDeploy.TerminationGracePeriodSeconds < 0 || crDeploy.TerminationGracePeriodSeconds > maxTerminationGracePeriod {
+		return nil, fmt.Errorf("terminationGracePeriodSeconds (%d) must be between 0 and %d", crDeploy.TerminationGracePeriodSeconds, maxTerminationGracePeriod)
+	}
+	// the env file is parsed here only as early validation; Deploy re-reads
+	// it every run so dev-loop redeploys pick up edits to the file.
+	var envFileVars map[string]string
+	if crDeploy.EnvFile != "" {
+		var err error
+		envFileVars, err = util.ParseEnvFile(crDeploy.EnvFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Cloud Run env file: %w", err)
+		}
+	}
+	if crDeploy.UploadRepo != "" {
+		repo, err := name.NewRepository(crDeploy.UploadRepo, name.WeakValidation)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse uploadRepo %q: %w", crDeploy.UploadRepo, err)
+		}
+		if !supportedRunRegistry(repo.Registry.Name()) {
+			return nil, fmt.Errorf("uploadRepo %q is hosted on %q: Cloud Run can only pull from Container Registry (*gcr.io) or Artifact Registry (*-docker.pkg.dev)", crDeploy.UploadRepo, repo.Registry.Name())
+		}
+	}
+	retryAttempts := defaultRetryAttempts
+	retryBaseDelay := defaultRetryBackoff
+	retryMaxDelay := defaultRetryMaxBackoff
+	if rc := crDeploy.RetryConfig; rc != nil {
+		if rc.MaxAttempts != 0 {
+			if rc.MaxAttempts < 0 {
+				return nil, fmt.Errorf("retryConfig.maxAttempts (%d) must be positive", rc.MaxAttempts)
+			}
+			retryAttempts = rc.MaxAttempts
+		}
+		if rc.BaseDelay != "" {
+			delay, err := time.ParseDuration(rc.BaseDelay)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse retryConfig.baseDelay %q: %w", rc.BaseDelay, err)
+			}
+			if delay <= 0 {
+				return nil, fmt.Errorf("retryConfig.baseDelay %q must be positive", rc.BaseDelay)
+			}
+			retryBaseDelay = delay
+		}
+		if rc.MaxDelay != "" {
+			delay, err := time.ParseDuration(rc.MaxDelay)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse retryConfig.maxDelay %q: %w", rc.MaxDelay, err)
+			}
+			if delay <= 0 {
+				return nil, fmt.Errorf("retryConfig.maxDelay %q must be positive", rc.MaxDelay)
+			}
+			retryMaxDelay = delay
+		}
+		if retryMaxDelay < retryBaseDelay {
+			if rc.MaxDelay != "" {
+				return nil, fmt.Errorf("retryConfig.maxDelay (%s) must not be less than baseDelay (%s)", retryMaxDelay, retryBaseDelay)
+			}
+			retryMaxDelay = retryBaseDelay
+		}
+	}
+	for key, value := range crDeploy.DefaultLabels {
+		if !gcpLabelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid defaultLabels key %q: keys must start with a lowercase letter and use at most 63 lowercase letters, digits, '_' or '-'", key)
+		}
+		if !gcpLabelValuePattern.MatchString(value) {
+			return nil, fmt.Errorf("invalid defaultLabels value %q for key %q: values must use at most 63 lowercase letters, digits, '_' or '-'", value, key)
+		}
+	}
+	if sp := crDeploy.StartupProbe; sp != nil {
+		for _, field := range []struct {
+			name  string
+			value int
+		}{
+			{"initialDelaySeconds", sp.InitialDelaySeconds},
+			{"timeoutSeconds", sp.TimeoutSeconds},
+			{"periodSeconds", sp.PeriodSeconds},
+			{"failureThreshold", sp.FailureThreshold},
+		} {
+			if field.value < 0 {
+				return nil, fmt.Errorf("startupProbe.%s (%d) must be positive", field.name, field.value)
+			}
+		}
+	}
+	var clientOptions []option.ClientOption
+	// the endpoint applies to every Cloud Run client: deploy, delete and the
+	// status monitor.
+	if crDeploy.APIEndpoint != "" {
+		clientOptions = append(clientOptions, option.WithEndpoint(crDeploy.APIEndpoint))
+	}
+	if crDeploy.CredentialsFile != "" {
+		if _, err := os.Stat(crDeploy.CredentialsFile); err != nil {
+			return nil, fmt.Errorf("unable to read Cloud Run credentials file %q: %w", crDeploy.CredentialsFile, err)
+		}
+		clientOptions = append(clientOptions, option.WithCredentialsFile(crDeploy.CredentialsFile))
+	}
+	// The run context usually also carries docker configuration, needed when
+	// images have to be copied into uploadRepo.
+	dockerCfg, _ := cfg.(docker.Config)
+	d := &Deployer{
+		configName:             configName,
+		Project:                crDeploy.ProjectID,
+		Region:                 region,
+		containerDependencies:  crDeploy.ContainerDependencies,
+		readinessTimeout:       readinessTimeout,
+		readyConditionType:     crDeploy.ReadyConditionType,
+		annotationStrategy:     crDeploy.AnnotationMergeStrategy,
+		pinDigest:              crDeploy.PinDigest,
+		maxRevisions:           crDeploy.MaxRevisions,
+		changeCause:            changeCause,
+		description:            crDeploy.Description,
+		launchStage:            crDeploy.LaunchStage,
+		useHTTP2:               crDeploy.UseHTTP2,
+		serviceAccount:         crDeploy.ServiceAccount,
+		vpcConnector:           crDeploy.VPCConnector,
+		vpcEgress:              crDeploy.VPCEgress,
+		containerConcurrency:   crDeploy.ContainerConcurrency,
+		traffic:                crDeploy.Traffic,
+		terminationGracePeriod: crDeploy.TerminationGracePeriodSeconds,
+		minInstances:           crDeploy.MinInstances,
+		maxInstances:           crDeploy.MaxInstances,
+		uploadRepo:             crDeploy.UploadRepo,
+		dockerConfig:           dockerCfg,
+		reportImageDiff:        os.Getenv("SKAFFOLD_CLOUD_RUN_IMAGE_DIFF") != "",
+		appliedServiceFile:     crDeploy.AppliedServiceFile,
+		buildArtifactsURL:      crDeploy.BuildArtifactsURL,
+		templateManifests:      crDeploy.TemplateManifests,
+		startupProbe:           crDeploy.StartupProbe,
+		strictContainerLimit:   crDeploy.StrictContainerLimit,
+		defaultLabels:          crDeploy.DefaultLabels,
+		retryAttempts:          retryAttempts,
+		retryBaseDelay:         retryBaseDelay,
+		retryMaxDelay:          retryMaxDelay,
+		apiEndpoint:            crDeploy.APIEndpoint,
+		validatePlatforms:      crDeploy.ValidatePlatforms,
+		executeJob:             crDeploy.ExecuteJob,
+		waitForDeletion:        crDeploy.WaitForDeletion,
+		forceDelete:            crDeploy.ForceDelete,
+		env:                    crDeploy.Env,
+		envFileVars:            envFileVars,
+		envFile:                crDeploy.EnvFile,
+		credentialsFile:        crDeploy.CredentialsFile,
+		secretEnv:              crDeploy.SecretEnv,
+		envPrefix:              crDeploy.EnvPrefix,
+		clientOptions:          clientOptions,
 		// TODO: implement logger for Cloud Run.
 		logger:        &log.NoopLogger{},
 		accessor:      NewAccessor(cfg, labeller.GetRunID()),
 		labeller:      labeller,
 		useGcpOptions: true,
-	}, nil
+		userAgent:     version.UserAgent(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
 }
 
-// Deploy creates a Cloud Run service using the provided manifest.
+// Deploy creates a Cloud Run service using the provided manifest. Each manifest
+// is matched to its built image by name, so several services can be deployed
+// from one config; they are deployed concurrently.
 func (d *Deployer) Deploy(ctx context.Context, out io.Writer, artifacts []graph.Artifact, manifestsByConfig manifest.ManifestListByConfig) error {
 	manifests := manifestsByConfig.GetForConfig(d.ConfigName())
 
-	for _, manifest := range manifests {
-		if err := d.deployToCloudRun(ctx, out, manifest); err != nil {
+	if d.validatePlatforms {
+		if err := validateImagePlatforms(artifacts); err != nil {
 			return err
 		}
 	}
-	return nil
+
+	// the env file is re-read on every deploy so a dev-loop redeploy triggered
+	// by an edit to the watched file injects the new values rather than the
+	// ones cached at construction.
+	if d.envFile != "" {
+		envFileVars, err := util.ParseEnvFile(d.envFile)
+		if err != nil {
+			return fmt.Errorf("unable to read Cloud Run env file: %w", err)
+		}
+		d.envFileVars = envFileVars
+	}
+
+	// the applied service file is truncated up front so after the deploy it
+	// holds exactly the services this run applied.
+	if d.appliedServiceFile != "" {
+		if err := os.WriteFile(d.appliedServiceFile, nil, 0644); err != nil {
+			return fmt.Errorf("unable to create applied service file %q: %w", d.appliedServiceFile, err)
+		}
+	}
+
+	// artifacts built by another pipeline are fetched up front and merged in,
+	// so the per-manifest deploys see one substitution set.
+	if d.buildArtifactsURL != "" {
+		fetched, err := fetchBuildArtifacts(ctx, d.buildArtifactsURL)
+		if err != nil {
+			return err
+		}
+		artifacts = mergeBuildArtifacts(artifacts, fetched)
+	}
+
+	// images hosted outside a registry Cloud Run can pull from are copied into
+	// the configured uploadRepo once, before the per-manifest deploys start.
+	artifacts, err := d.uploadMissingImages(out, artifacts)
+	if err != nil {
+		return err
+	}
+
+	validateImageRegionColocation(artifacts, d.Region)
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for _, manifest := range manifests {
+		manifest := manifest
+		g.Go(func() error {
+			return d.deployToCloudRun(gCtx, out, manifest, artifacts)
+		})
+	}
+	return g.Wait()
 }
 
 func (d *Deployer) ConfigName() string {
 	return d.configName
 }
 
+// Render writes the Cloud Run service manifests exactly as Deploy would send
+// them to the API, with built images substituted and the configured annotations
+// applied. Rendering never contacts the Cloud Run API, so the offline flag is
+// accepted only for signature compatibility. Output goes to the file at
+// filepath when set, to out otherwise.
+func (d *Deployer) Render(ctx context.Context, out io.Writer, artifacts []graph.Artifact, _ bool, filepath string, manifestsByConfig manifest.ManifestListByConfig) error {
+	w := out
+	if filepath != "" {
+		file, err := os.Create(filepath)
+		if err != nil {
+			return fmt.Errorf("unable to create render output file %q: %w", filepath, err)
+		}
+		defer file.Close()
+		w = file
+	}
+	manifests := manifestsByConfig.GetForConfig(d.ConfigName())
+	for i, m := range manifests {
+		resource := &unstructured.Unstructured{}
+		if err := k8syaml.Unmarshal(m, resource); err != nil {
+			return sErrors.NewError(fmt.Errorf("unable to unmarshal Cloud Run Service config"), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+			})
+		}
+		rendered := m
+		// only Services are processed on deploy; other kinds pass through unchanged.
+		if resource.GetAPIVersion() == "serving.knative.dev/v1" && resource.GetKind() == "Service" {
+			service, err := d.prepareService(m, artifacts)
+			if err != nil {
+				return err
+			}
+			b, err := k8syaml.Marshal(service)
+			if err != nil {
+				return fmt.Errorf("unable to marshal rendered Cloud Run Service: %w", err)
+			}
+			rendered = b
+		}
+		if i > 0 {
+			fmt.Fprintln(w, "---")
+		}
+		if _, err := w.Write(rendered); err != nil {
+			return fmt.Errorf("unable to write rendered manifest: %w", err)
+		}
+	}
+	return nil
+}
+
 // Dependencies list the files that would trigger a redeploy
+// Dependencies lists the local files the deploy reads, so dev mode redeploys
+// when any of them change. Absolute paths are skipped: the watcher resolves
+// paths against the workspace, and system paths such as credential files kept
+// outside it should not feed the file watcher.
 func (d *Deployer) Dependencies() ([]string, error) {
-	return []string{}, nil
+	deps := []string{}
+	seen := map[string]bool{}
+	for _, path := range []string{d.envFile, d.credentialsFile} {
+		if path == "" || filepath.IsAbs(path) || seen[path] {
+			continue
+		}
+		seen[path] = true
+		deps = append(deps, path)
+	}
+	return deps, nil
 }
 
 // Cleanup deletes the created Cloud Run services
@@ -123,8 +642,8 @@ func (d *Deployer) GetAccessor() access.Accessor {
 }
 
 // GetSyncer gets the file syncer for Cloud Run. Not supported by this deployer.
-func (d *Deployer) GetSyncer() sync.Syncer {
-	return &sync.NoopSyncer{}
+func (d *Deployer) GetSyncer() pkgsync.Syncer {
+	return &pkgsync.NoopSyncer{}
 }
 
 // TrackBuildArtifacts is not supported by this deployer.
@@ -145,22 +664,410 @@ func (d *Deployer) GetStatusMonitor() status.Monitor {
 func (d *Deployer) getMonitor() *Monitor {
 	if d.monitor == nil {
 		d.monitor = NewMonitor(d.labeller, d.clientOptions)
+		if d.readinessTimeout > 0 && d.readinessTimeout < d.monitor.statusCheckDeadline {
+			d.monitor.statusCheckDeadline = d.readinessTimeout
+		}
+		if d.readyConditionType != "" {
+			d.monitor.readyConditionType = d.readyConditionType
+		}
 	}
 	return d.monitor
 }
-func (d *Deployer) deployToCloudRun(ctx context.Context, out io.Writer, manifest []byte) error {
-	cOptions := d.clientOptions
+
+// gcpLabelKeyPattern and gcpLabelValuePattern encode GCP's label constraints:
+// keys start with a lowercase letter and both keys and values are at most 63
+// characters of lowercase letters, digits, '_' and '-'.
+var (
+	gcpLabelKeyPattern   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	gcpLabelValuePattern = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+// validateLabels rejects service and revision labels that GCP would refuse,
+// naming the offending key so the user can fix it in their config.
+func validateLabels(service *run.Service) error {
+	labelSets := []map[string]string{service.Metadata.Labels}
+	if service.Spec != nil && service.Spec.Template != nil && service.Spec.Template.Metadata != nil {
+		labelSets = append(labelSets, service.Spec.Template.Metadata.Labels)
+	}
+	for _, labels := range labelSets {
+		for key, value := range labels {
+			if !gcpLabelKeyPattern.MatchString(key) {
+				return sErrors.NewError(fmt.Errorf("invalid Cloud Run label key %q", key), &proto.ActionableErr{
+					Message: fmt.Sprintf("label key %q is not a valid Cloud Run label: keys must start with a lowercase letter and use at most 63 lowercase letters, digits, '_' or '-'", key),
+					ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+				})
+			}
+			if !gcpLabelValuePattern.MatchString(value) {
+				return sErrors.NewError(fmt.Errorf("invalid Cloud Run label value %q for key %q", value, key), &proto.ActionableErr{
+					Message: fmt.Sprintf("label value %q for key %q is not a valid Cloud Run label: values must use at most 63 lowercase letters, digits, '_' or '-'", value, key),
+					ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// applyDefaultLabels stamps the configured default labels on the service and
+// revision template. They sit beneath every other label source: keys already
+// present from the manifest stay, and skaffold's run-id labels, applied
+// afterwards, win on collision.
+func (d *Deployer) applyDefaultLabels(service *run.Service) {
+	if len(d.defaultLabels) == 0 {
+		return
+	}
+	labelSets := []*map[string]string{&service.Metadata.Labels}
+	if service.Spec != nil && service.Spec.Template != nil {
+		if service.Spec.Template.Metadata == nil {
+			service.Spec.Template.Metadata = &run.ObjectMeta{}
+		}
+		labelSets = append(labelSets, &service.Spec.Template.Metadata.Labels)
+	}
+	for key, value := range d.defaultLabels {
+		for _, labelSet := range labelSets {
+			if *labelSet == nil {
+				*labelSet = map[string]string{}
+			}
+			if _, present := (*labelSet)[key]; !present {
+				(*labelSet)[key] = value
+			}
+		}
+	}
+}
+
+// applySkaffoldLabels merges the labeller's labels into the service and
+// revision template metadata so deployed services carry skaffold's run-id for
+// cleanup and filtering. The labels are sanitized to Cloud Run's rules first,
+// since an invalid key or value would make the API reject the whole deploy.
+func (d *Deployer) applySkaffoldLabels(service *run.Service) {
+	if d.labeller == nil {
+		return
+	}
+	labels := d.labeller.Labels()
+	if len(labels) == 0 {
+		return
+	}
+	labelSets := []*map[string]string{&service.Metadata.Labels}
+	if service.Spec != nil && service.Spec.Template != nil {
+		if service.Spec.Template.Metadata == nil {
+			service.Spec.Template.Metadata = &run.ObjectMeta{}
+		}
+		labelSets = append(labelSets, &service.Spec.Template.Metadata.Labels)
+	}
+	for key, value := range labels {
+		key, value, ok := sanitizeGcpLabel(key, value)
+		if !ok {
+			continue
+		}
+		for _, labelSet := range labelSets {
+			if *labelSet == nil {
+				*labelSet = map[string]string{}
+			}
+			(*labelSet)[key] = value
+		}
+	}
+}
+
+// sanitizeGcpLabel converts a Kubernetes-style label into one Cloud Run
+// accepts: the domain prefix is dropped, uppercase is lowered, unsupported
+// characters become '-' and both parts are truncated to 63 characters. Keys
+// that cannot be salvaged are reported as not ok and should be skipped.
+func sanitizeGcpLabel(key, value string) (string, string, bool) {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		key = key[i+1:]
+	}
+	key = sanitizeGcpLabelPart(key)
+	value = sanitizeGcpLabelPart(value)
+	if !gcpLabelKeyPattern.MatchString(key) || !gcpLabelValuePattern.MatchString(value) {
+		return "", "", false
+	}
+	return key, value, true
+}
+
+func sanitizeGcpLabelPart(part string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(part) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	sanitized := b.String()
+	if len(sanitized) > 63 {
+		sanitized = sanitized[:63]
+	}
+	return sanitized
+}
+
+// supportedRunRegistry reports whether Cloud Run can pull images from the given
+// registry: Container Registry (*gcr.io) and Artifact Registry (*-docker.pkg.dev).
+func supportedRunRegistry(registry string) bool {
+	return registry == "gcr.io" || strings.HasSuffix(registry, ".gcr.io") || strings.HasSuffix(registry, "-docker.pkg.dev")
+}
+
+// validateImageRegistries rejects built images that Cloud Run cannot pull,
+// naming the offending host.
+func validateImageRegistries(artifacts []graph.Artifact) error {
+	for _, artifact := range artifacts {
+		ref, err := name.ParseReference(artifact.Tag, name.WeakValidation)
+		if err != nil {
+			return sErrors.NewError(fmt.Errorf("unable to parse image name %q: %w", artifact.Tag, err), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+			})
+		}
+		registry := ref.Context().Registry.Name()
+		if supportedRunRegistry(registry) {
+			continue
+		}
+		return sErrors.NewError(fmt.Errorf("image %q is hosted on %q which Cloud Run cannot pull from", artifact.Tag, registry), &proto.ActionableErr{
+			Message: fmt.Sprintf("image %q is hosted on %q: Cloud Run only deploys images from Container Registry (*gcr.io) or Artifact Registry (*-docker.pkg.dev)", artifact.Tag, registry),
+			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
+		})
+	}
+	return nil
+}
+
+// registryLocation reports the Artifact Registry or Container Registry
+// location an image host serves from: a region like `us-central1` or a
+// multi-region like `us`. Empty when the host carries no location.
+func registryLocation(registry string) string {
+	switch registry {
+	case "gcr.io", "us.gcr.io":
+		return "us"
+	case "eu.gcr.io":
+		return "europe"
+	case "asia.gcr.io":
+		return "asia"
+	}
+	if strings.HasSuffix(registry, "-docker.pkg.dev") {
+		return strings.TrimSuffix(registry, "-docker.pkg.dev")
+	}
+	return ""
+}
+
+// validateImageRegionColocation warns when a built image is hosted in a
+// different region than the one being deployed to: pulls then cross regions,
+// adding latency and egress costs. A multi-region host like `us` counts as
+// co-located with every region inside it.
+func validateImageRegionColocation(artifacts []graph.Artifact, region string) {
+	if region == "" {
+		return
+	}
+	for _, artifact := range artifacts {
+		ref, err := name.ParseReference(artifact.Tag, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		location := registryLocation(ref.Context().Registry.Name())
+		if location == "" || location == region || strings.HasPrefix(region, location+"-") {
+			continue
+		}
+		warnings.Printf("image %q is hosted in %q but deploys to Cloud Run region %q: co-locating them avoids cross-region latency and egress costs", artifact.Tag, location, region)
+	}
+}
+
+// getImagePlatforms fetches the platforms an image is built for. Overridable
+// for testing.
+var getImagePlatforms = docker.GetPlatforms
+
+// validateImagePlatforms rejects built images that cannot run on managed Cloud
+// Run, which only runs linux/amd64. Images whose platform cannot be determined
+// are let through; any real problem surfaces during the deploy.
+func validateImagePlatforms(artifacts []graph.Artifact) error {
+	for _, artifact := range artifacts {
+		platforms, err := getImagePlatforms(artifact.Tag)
+		if err != nil {
+			olog.Entry(context.TODO()).Debugf("unable to determine platforms for %q: %v", artifact.Tag, err)
+			continue
+		}
+		if len(platforms) == 0 {
+			continue
+		}
+		var built []string
+		for _, p := range platforms {
+			if p.Architecture == "amd64" {
+				built = nil
+				break
+			}
+			built = append(built, fmt.Sprintf("%s/%s", p.OS, p.Architecture))
+		}
+		if len(built) > 0 {
+			return sErrors.NewError(fmt.Errorf("image %q is built for %s: managed Cloud Run only runs linux/amd64 images", artifact.Tag, strings.Join(built, ", ")), &proto.ActionableErr{
+				Message: fmt.Sprintf("image %q is built for %s: managed Cloud Run only runs linux/amd64 images. Rebuild %q for linux/amd64, for example by setting `--platform=linux/amd64`.", artifact.Tag, strings.Join(built, ", "), artifact.ImageName),
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
+			})
+		}
+	}
+	return nil
+}
+
+// copyImageToRepo retags src as target in the remote registry. Overridable for
+// testing.
+var copyImageToRepo = func(src, target string, cfg docker.Config) error {
+	return docker.AddRemoteTag(src, target, cfg, nil)
+}
+
+// uploadMissingImages copies built images that Cloud Run cannot pull into the
+// configured uploadRepo, returning the artifact list with the re-homed tags.
+// Images already hosted in a supported registry are left untouched.
+func (d *Deployer) uploadMissingImages(out io.Writer, artifacts []graph.Artifact) ([]graph.Artifact, error) {
+	if d.uploadRepo == "" {
+		return artifacts, nil
+	}
+	uploaded := make([]graph.Artifact, len(artifacts))
+	for i, artifact := range artifacts {
+		uploaded[i] = artifact
+		ref, err := name.ParseReference(artifact.Tag, name.WeakValidation)
+		if err != nil {
+			return nil, sErrors.NewError(fmt.Errorf("unable to parse image name %q: %w", artifact.Tag, err), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+			})
+		}
+		if supportedRunRegistry(ref.Context().Registry.Name()) {
+			continue
+		}
+		if d.dockerConfig == nil {
+			return nil, fmt.Errorf("uploadRepo is set but no docker configuration is available to copy %q", artifact.Tag)
+		}
+		target := fmt.Sprintf("%s/%s", strings.TrimSuffix(d.uploadRepo, "/"), path.Base(ref.Context().RepositoryStr()))
+		switch r := ref.(type) {
+		case name.Tag:
+			target = fmt.Sprintf("%s:%s", target, r.TagStr())
+		case name.Digest:
+			target = fmt.Sprintf("%s@%s", target, r.DigestStr())
+		}
+		output.Default.Fprintf(out, "Copying image %s to %s so Cloud Run can pull it\n", artifact.Tag, target)
+		if err := copyImageToRepo(artifact.Tag, target, d.dockerConfig); err != nil {
+			return nil, sErrors.NewError(fmt.Errorf("unable to copy image %q to %q: %w", artifact.Tag, target, err), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
+			})
+		}
+		uploaded[i].Tag = target
+	}
+	return uploaded, nil
+}
+
+// buildOutput mirrors the JSON written by `skaffold build --file-output`.
+type buildOutput struct {
+	Builds []graph.Artifact `json:"builds"`
+}
+
+// fetchBuildArtifacts downloads the build artifacts JSON served at url, as
+// written by `skaffold build --file-output`, so a build from another pipeline
+// can feed this deploy's image substitution.
+func fetchBuildArtifacts(ctx context.Context, url string) ([]graph.Artifact, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid build artifacts URL %q: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, sErrors.NewError(fmt.Errorf("unable to fetch build artifacts from %q: %w", url, err), &proto.ActionableErr{
+			Message: fmt.Sprintf("unable to fetch build artifacts from %q: %s", url, err),
+			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+		})
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, sErrors.NewError(fmt.Errorf("unable to fetch build artifacts from %q: status %s", url, resp.Status), &proto.ActionableErr{
+			Message: fmt.Sprintf("unable to fetch build artifacts from %q: status %s", url, resp.Status),
+			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+		})
+	}
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read build artifacts from %q: %w", url, err)
+	}
+	var output buildOutput
+	if err := json.Unmarshal(buf, &output); err != nil {
+		return nil, sErrors.NewError(fmt.Errorf("build artifacts at %q are not valid JSON: %w", url, err), &proto.ActionableErr{
+			Message: fmt.Sprintf("build artifacts at %q are not valid JSON: %s", url, err),
+			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+		})
+	}
+	for _, b := range output.Builds {
+		if b.ImageName == "" || b.Tag == "" {
+			return nil, sErrors.NewError(fmt.Errorf("build artifacts at %q are malformed: every build needs an imageName and a tag", url), &proto.ActionableErr{
+				Message: fmt.Sprintf("build artifacts at %q are malformed: every build needs an imageName and a tag", url),
+				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+			})
+		}
+	}
+	return output.Builds, nil
+}
+
+// mergeBuildArtifacts combines locally built artifacts with fetched ones.
+// Local artifacts win when both list the same image name.
+func mergeBuildArtifacts(local, fetched []graph.Artifact) []graph.Artifact {
+	seen := make(map[string]bool, len(local))
+	for _, a := range local {
+		seen[a.ImageName] = true
+	}
+	merged := local
+	for _, a := range fetched {
+		if !seen[a.ImageName] {
+			merged = append(merged, a)
+		}
+	}
+	return merged
+}
+
+// newRunClient builds the Cloud Run API client. Overridable for testing.
+var newRunClient = run.NewService
+
+// client returns the Cloud Run API client, building it on first use and
+// reusing it afterwards. The client outlives the call it was first built for,
+// so it is constructed against the background context rather than ctx: a
+// Deploy's context expiring must not break a later Cleanup.
+func (d *Deployer) client(ctx context.Context) (*run.APIService, error) {
+	d.clientMu.Lock()
+	defer d.clientMu.Unlock()
+	if d.crclient != nil {
+		return d.crclient, nil
+	}
+	cOptions := append([]option.ClientOption{option.WithUserAgent(d.userAgent)}, d.clientOptions...)
 	if d.useGcpOptions {
-		cOptions = append(cOptions, option.WithEndpoint(fmt.Sprintf("%s-run.googleapis.com", d.Region)))
+		// an explicitly configured endpoint is already part of clientOptions;
+		// only fall back to the regional default without one.
+		if d.apiEndpoint == "" {
+			cOptions = append(cOptions, option.WithEndpoint(fmt.Sprintf("%s-run.googleapis.com", d.Region)))
+		}
 		cOptions = append(gcp.ClientOptions(ctx), cOptions...)
 	}
-	crclient, err := run.NewService(ctx, cOptions...)
+	crclient, err := newRunClient(context.Background(), cOptions...)
 	if err != nil {
-		return sErrors.NewError(fmt.Errorf("unable to create Cloud Run Client"), &proto.ActionableErr{
+		return nil, sErrors.NewError(fmt.Errorf("unable to create Cloud Run Client"), &proto.ActionableErr{
 			Message: err.Error(),
 			ErrCode: proto.StatusCode_DEPLOY_GET_CLOUD_RUN_CLIENT_ERR,
 		})
 	}
+	d.crclient = crclient
+	return crclient, nil
+}
+
+func (d *Deployer) deployToCloudRun(ctx context.Context, out io.Writer, manifest []byte, artifacts []graph.Artifact) error {
+	if err := validateImageRegistries(artifacts); err != nil {
+		return err
+	}
+	crclient, err := d.client(ctx)
+	if err != nil {
+		return err
+	}
+	if d.templateManifests {
+		expanded, err := util.ExpandEnvTemplateOrFail(string(manifest), nil)
+		if err != nil {
+			return sErrors.NewError(fmt.Errorf("unable to expand template in Cloud Run manifest: %w", err), &proto.ActionableErr{
+				Message: fmt.Sprintf("unable to expand template in Cloud Run manifest: %s", err),
+				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+			})
+		}
+		manifest = []byte(expanded)
+	}
 	// figure out which type we have:
 	resource := &unstructured.Unstructured{}
 	if err = k8syaml.Unmarshal(manifest, resource); err != nil {
@@ -172,10 +1079,15 @@ func (d *Deployer) deployToCloudRun(ctx context.Context, out io.Writer, manifest
 	var resName *RunResourceName
 	switch {
 	case resource.GetAPIVersion() == "serving.knative.dev/v1" && resource.GetKind() == "Service":
-		resName, err = d.deployService(crclient, manifest, out)
+		resName, err = d.deployService(crclient, manifest, out, artifacts)
 		// the accessor only supports services. Jobs don't run by themselves so port forwarding doesn't make sense.
 		if resName != nil {
+			d.resourcesMu.Lock()
 			d.accessor.AddResource(*resName)
+			d.resourcesMu.Unlock()
+		}
+		if err == nil {
+			err = d.pruneRevisions(crclient, resName)
 		}
 	case resource.GetAPIVersion() == "run.googleapis.com/v1" && resource.GetKind() == "Job":
 		resName, err = d.deployJob(crclient, manifest, out)
@@ -191,11 +1103,15 @@ func (d *Deployer) deployToCloudRun(ctx context.Context, out io.Writer, manifest
 		return err
 	}
 
+	d.resourcesMu.Lock()
 	d.getMonitor().Resources = append(d.getMonitor().Resources, *resName)
+	d.resourcesMu.Unlock()
 	return nil
 }
 
-func (d *Deployer) deployService(crclient *run.APIService, manifest []byte, out io.Writer) (*RunResourceName, error) {
+// prepareService turns a Service manifest into the run.Service Deploy sends to
+// the API: built images are substituted and the configured annotations applied.
+func (d *Deployer) prepareService(manifest []byte, artifacts []graph.Artifact) (*run.Service, error) {
 	service := &run.Service{}
 	if err := k8syaml.Unmarshal(manifest, service); err != nil {
 		return nil, sErrors.NewError(fmt.Errorf("unable to unmarshal Cloud Run Service config"), &proto.ActionableErr{
@@ -203,6 +1119,32 @@ func (d *Deployer) deployService(crclient *run.APIService, manifest []byte, out
 			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
 		})
 	}
+	if err := validateServiceConfig(service); err != nil {
+		return nil, err
+	}
+	if err := validateNoTemplateRemnants(service); err != nil {
+		return nil, err
+	}
+	if err := validateContainerLimit(service, d.strictContainerLimit); err != nil {
+		return nil, err
+	}
+	substituteBuiltImages(service, artifacts)
+	d.applyAnnotationStrategy(service)
+	if err := d.applyContainerDependencies(service); err != nil {
+		return nil, err
+	}
+	d.pinImageDigests(service)
+	d.applyChangeCause(service)
+	d.applyDescription(service)
+	d.applyLaunchStage(service)
+	d.applyHTTP2(service)
+	d.applyServiceAccount(service)
+	d.applyVPCAccess(service)
+	d.applyScaling(service)
+	d.applyStartupProbe(service)
+	d.applyTraffic(service)
+	d.applyTerminationGracePeriod(service)
+	d.applyEnv(service)
 	if d.Project != "" {
 		service.Metadata.Namespace = d.Project
 	} else if service.Metadata.Namespace == "" {
@@ -224,17 +1166,36 @@ func (d *Deployer) deployService(crclient *run.APIService, manifest []byte, out
 			service.Spec.Template.Metadata.Labels["run-id"] = runID
 		}
 	}
+	d.applyDefaultLabels(service)
+	d.applySkaffoldLabels(service)
+	if err := validateLabels(service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+func (d *Deployer) deployService(crclient *run.APIService, manifest []byte, out io.Writer, artifacts []graph.Artifact) (*RunResourceName, error) {
+	service, err := d.prepareService(manifest, artifacts)
+	if err != nil {
+		return nil, err
+	}
 	resName := RunResourceName{
 		Project: service.Metadata.Namespace,
 		Region:  d.Region,
 		Service: service.Metadata.Name,
 	}
 	output.Default.Fprintln(out, "Deploying Cloud Run service:\n\t", service.Metadata.Name)
-	parent := fmt.Sprintf("projects/%s/locations/%s", service.Metadata.Namespace, d.Region)
+	// the full service config is noisy, so only echo it at verbose/debug logging levels.
+	if isVerbose() {
+		if b, err := k8syaml.Marshal(service); err == nil {
+			output.Blue.Fprintf(out, "%s\n", b)
+		}
+	}
+	parent := fmt.Sprintf("projects/%s/locations/%s", service.Metadata.Namespace, d.Region)
 
 	sName := resName.String()
 	getCall := crclient.Projects.Locations.Services.Get(sName)
-	_, err := getCall.Do()
+	current, err := getCall.Do()
 
 	if err != nil {
 		gErr, ok := err.(*googleapi.Error)
@@ -244,12 +1205,23 @@ func (d *Deployer) deployService(crclient *run.APIService, manifest []byte, out
 				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_GET_SERVICE_ERR,
 			})
 		}
+	}
+	if d.reportImageDiff {
+		printImageDiff(out, current, service)
+	}
+	if current == nil {
 		// This is a new service, we need to create it
 		createCall := crclient.Projects.Locations.Services.Create(parent, service)
-		_, err = createCall.Do()
+		err = d.withRetries(out, func() error {
+			_, err := createCall.Do()
+			return err
+		})
 	} else {
 		replaceCall := crclient.Projects.Locations.Services.ReplaceService(sName, service)
-		_, err = replaceCall.Do()
+		err = d.withRetries(out, func() error {
+			_, err := replaceCall.Do()
+			return err
+		})
 	}
 	if err != nil {
 		return nil, sErrors.NewError(fmt.Errorf("error deploying Cloud Run Service: %s", err), &proto.ActionableErr{
@@ -257,9 +1229,570 @@ func (d *Deployer) deployService(crclient *run.APIService, manifest []byte, out
 			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
 		})
 	}
+	if d.appliedServiceFile != "" {
+		if err := d.writeAppliedService(service); err != nil {
+			return nil, err
+		}
+	}
+	if url := d.fetchServiceURL(crclient, sName); url != "" {
+		output.Green.Fprintln(out, fmt.Sprintf("Cloud Run service %s is available at %s", service.Metadata.Name, url))
+	}
 	return &resName, nil
 }
 
+// writeAppliedService appends the service configuration as sent to the Cloud
+// Run API to the configured appliedServiceFile, separating documents when
+// several services deploy in one run. Deploy truncates the file first so it
+// always reflects the latest deploy.
+func (d *Deployer) writeAppliedService(service *run.Service) error {
+	b, err := k8syaml.Marshal(service)
+	if err != nil {
+		return fmt.Errorf("unable to marshal applied Cloud Run Service: %w", err)
+	}
+	d.appliedServiceMu.Lock()
+	defer d.appliedServiceMu.Unlock()
+	f, err := os.OpenFile(d.appliedServiceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open applied service file %q: %w", d.appliedServiceFile, err)
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		b = append([]byte("---\n"), b...)
+	}
+	if _, err := f.Write(b); err != nil {
+		return fmt.Errorf("unable to write applied service file %q: %w", d.appliedServiceFile, err)
+	}
+	return nil
+}
+
+// serviceURLPollAttempts and serviceURLPollInterval bound how long deploy
+// waits for Cloud Run to report the service URL after a create or replace.
+// The URL is usually available immediately; when it is not, the status
+// monitor reports it once the service is ready.
+var (
+	serviceURLPollAttempts = 3
+	serviceURLPollInterval = 200 * time.Millisecond
+)
+
+// fetchServiceURL re-fetches the deployed service to report where it is
+// reachable, polling briefly when the URL is not populated yet.
+func (d *Deployer) fetchServiceURL(crclient *run.APIService, sName string) string {
+	for attempt := 0; ; attempt++ {
+		res, err := crclient.Projects.Locations.Services.Get(sName).Do()
+		if err == nil && res.Status != nil && res.Status.Url != "" {
+			return res.Status.Url
+		}
+		if attempt == serviceURLPollAttempts-1 {
+			return ""
+		}
+		time.Sleep(serviceURLPollInterval)
+	}
+}
+
+// validateServiceConfig checks that the unmarshaled Service declares the fields the deploy
+// relies on before any of them are dereferenced, returning an actionable error instead of
+// panicking on a partial config.
+func validateServiceConfig(service *run.Service) error {
+	var missing string
+	switch {
+	case service.Metadata == nil || service.Metadata.Name == "":
+		missing = "metadata.name"
+	case service.Spec == nil || service.Spec.Template == nil:
+		missing = "spec.template"
+	case service.Spec.Template.Spec == nil || len(service.Spec.Template.Spec.Containers) == 0:
+		missing = "spec.template.spec.containers"
+	default:
+		return nil
+	}
+	return sErrors.NewError(fmt.Errorf("invalid Cloud Run Service config: %s is not defined", missing), &proto.ActionableErr{
+		Message: fmt.Sprintf("The Cloud Run Service manifest must define %s", missing),
+		ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+	})
+}
+
+// maxContainersPerService is the documented managed Cloud Run limit: one
+// ingress container plus up to nine sidecars.
+const maxContainersPerService = 10
+
+// validateContainerLimit checks the service against the managed Cloud Run
+// limit on containers per service. Over-limit services warn by default, since
+// the limit may be raised server-side before this constant catches up; with
+// strict set they fail with an actionable error instead.
+func validateContainerLimit(service *run.Service, strict bool) error {
+	count := len(service.Spec.Template.Spec.Containers)
+	if count <= maxContainersPerService {
+		return nil
+	}
+	detail := fmt.Sprintf("Cloud Run Service %q defines %d containers: managed Cloud Run allows at most %d per service", service.Metadata.Name, count, maxContainersPerService)
+	if !strict {
+		warnings.Printf("%s", detail)
+		return nil
+	}
+	return sErrors.NewError(fmt.Errorf("too many containers in Cloud Run Service %q: %d", service.Metadata.Name, count), &proto.ActionableErr{
+		Message: detail + ". Remove sidecar containers or split the service.",
+		ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+	})
+}
+
+// validateNoTemplateRemnants rejects service names or namespaces still containing
+// template markers, which indicate a variable the render step failed to resolve.
+func validateNoTemplateRemnants(service *run.Service) error {
+	for field, value := range map[string]string{
+		"metadata.name":      service.Metadata.Name,
+		"metadata.namespace": service.Metadata.Namespace,
+	} {
+		if strings.Contains(value, "{{") || strings.Contains(value, "${") {
+			return sErrors.NewError(fmt.Errorf("unresolved template variable in Cloud Run Service %s: %q", field, value), &proto.ActionableErr{
+				Message: fmt.Sprintf("The Cloud Run Service manifest %s %q still contains a template marker: check that every variable used in the manifest is set", field, value),
+				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+			})
+		}
+	}
+	return nil
+}
+
+// substituteBuiltImages replaces container images in the service template with the tagged
+// images produced by the build. All containers in the template are mapped, so sidecars and
+// startup dependencies declared via the `run.googleapis.com/container-dependencies`
+// annotation get the same substitution as the serving container.
+func substituteBuiltImages(service *run.Service, artifacts []graph.Artifact) {
+	if service.Spec == nil || service.Spec.Template == nil || service.Spec.Template.Spec == nil {
+		return
+	}
+	for _, container := range service.Spec.Template.Spec.Containers {
+		for _, artifact := range artifacts {
+			if container.Image == artifact.ImageName {
+				container.Image = artifact.Tag
+				break
+			}
+		}
+	}
+}
+
+// printImageDiff reports, per container, the image the service currently runs
+// and the one this deploy would apply, so image substitutions can be reviewed
+// before the service is replaced. current is nil for a new service.
+func printImageDiff(out io.Writer, current *run.Service, desired *run.Service) {
+	currentImages := map[string]string{}
+	if current != nil && current.Spec != nil && current.Spec.Template != nil && current.Spec.Template.Spec != nil {
+		for _, container := range current.Spec.Template.Spec.Containers {
+			currentImages[container.Name] = container.Image
+		}
+	}
+	output.Default.Fprintln(out, "Image changes for Cloud Run service:\n\t", desired.Metadata.Name)
+	for _, container := range desired.Spec.Template.Spec.Containers {
+		name := container.Name
+		if name == "" {
+			name = desired.Metadata.Name
+		}
+		old, found := currentImages[container.Name]
+		if !found {
+			old = "<none>"
+		}
+		output.Default.Fprintf(out, "\t%s: %s -> %s\n", name, old, container.Image)
+	}
+}
+
+// annotationStrategyMerge and annotationStrategyReplace are the supported
+// annotationMergeStrategy values. Merge is the default.
+const (
+	annotationStrategyMerge   = "merge"
+	annotationStrategyReplace = "replace"
+)
+
+// applyAnnotationStrategy decides how the annotations this config produces
+// combine with the ones already in the manifest. The default, merge, keeps the
+// manifest annotations and overwrites conflicting keys; replace discards the
+// manifest annotations so only the configured ones end up on the service.
+func (d *Deployer) applyAnnotationStrategy(service *run.Service) {
+	if d.annotationStrategy != annotationStrategyReplace {
+		return
+	}
+	service.Metadata.Annotations = nil
+	if service.Spec != nil && service.Spec.Template != nil && service.Spec.Template.Metadata != nil {
+		service.Spec.Template.Metadata.Annotations = nil
+	}
+}
+
+// containerDependenciesAnnotation is the revision annotation Cloud Run reads to
+// determine the container startup order.
+const containerDependenciesAnnotation = "run.googleapis.com/container-dependencies"
+
+// imageDigestsAnnotation records the digests of the deployed images, keyed by
+// repository, so deploys made by tag can still be audited.
+const imageDigestsAnnotation = "skaffold.dev/image-digests"
+
+// changeCauseAnnotation records why the deploy happened on the revision, in the
+// spirit of `kubernetes.io/change-cause`.
+const changeCauseAnnotation = "skaffold.dev/change-cause"
+
+// descriptionAnnotation is the service annotation the Cloud console renders as
+// the service description.
+const descriptionAnnotation = "run.googleapis.com/description"
+
+// launchStageAnnotation is the service annotation gating access to Cloud Run
+// features in pre-GA launch stages.
+const launchStageAnnotation = "run.googleapis.com/launch-stage"
+
+// maxDescriptionLength is the longest service description Cloud Run accepts.
+const maxDescriptionLength = 512
+
+// headCommitMessage returns the subject of the current git HEAD commit, used as
+// the default change cause. Overridable for testing.
+var headCommitMessage = func() string {
+	out, err := util.RunCmdOut(context.TODO(), exec.Command("git", "log", "-1", "--pretty=%s"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gcloudRunRegion returns the Cloud Run region from the active gcloud
+// configuration, used as a region fallback. Overridable for testing.
+var gcloudRunRegion = func() string {
+	out, err := util.RunCmdOut(context.TODO(), exec.Command("gcloud", "config", "get-value", "run/region"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyEnv merges the configured environment sources into the first container of
+// the revision template. Precedence, from highest to lowest: `env` from the
+// schema, variables from the env file, secret-backed variables, and finally env
+// already present in the service config file. Each key appears exactly once;
+// keys new to the manifest are appended in sorted order per source so repeated
+// deploys produce identical revisions.
+func (d *Deployer) applyEnv(service *run.Service) {
+	if len(d.env) == 0 && len(d.envFileVars) == 0 && len(d.secretEnv) == 0 {
+		return
+	}
+	containers := service.Spec.Template.Spec.Containers
+	if len(containers) == 0 {
+		return
+	}
+	if len(d.envPrefix) == 0 {
+		d.mergeEnv(containers[0], "")
+		return
+	}
+	// with envPrefix, each named container receives the environment with its
+	// own prefix, so containers can share variable names without collisions.
+	for _, container := range containers {
+		prefix, found := d.envPrefix[container.Name]
+		if !found {
+			continue
+		}
+		d.mergeEnv(container, prefix)
+	}
+}
+
+// mergeEnv merges the configured environment into the container's, prepending
+// prefix to the name of every injected variable.
+func (d *Deployer) mergeEnv(container *run.Container, prefix string) {
+	merged := make(map[string]*run.EnvVar)
+	var order []string
+	add := func(e *run.EnvVar) {
+		if _, seen := merged[e.Name]; !seen {
+			order = append(order, e.Name)
+		}
+		merged[e.Name] = e
+	}
+	// lowest precedence first: later sources overwrite earlier ones.
+	for _, e := range container.Env {
+		add(e)
+	}
+	for _, name := range sortedKeys(d.secretEnv) {
+		secret, version, found := strings.Cut(d.secretEnv[name], ":")
+		if !found {
+			version = "latest"
+		}
+		add(&run.EnvVar{Name: prefix + name, ValueFrom: &run.EnvVarSource{SecretKeyRef: &run.SecretKeySelector{
+			Name: secret,
+			Key:  version,
+		}}})
+	}
+	for _, name := range sortedKeys(d.envFileVars) {
+		add(&run.EnvVar{Name: prefix + name, Value: d.envFileVars[name]})
+	}
+	for _, name := range sortedKeys(d.env) {
+		add(&run.EnvVar{Name: prefix + name, Value: d.env[name]})
+	}
+	env := make([]*run.EnvVar, 0, len(order))
+	for _, name := range order {
+		env = append(env, merged[name])
+	}
+	container.Env = env
+}
+
+// applyTraffic records the configured traffic split on the service, overriding
+// Cloud Run's default of routing all traffic to the latest revision. Entries
+// with latestRevision target the revision this deploy creates; revisions can
+// only be pinned by name once they exist.
+func (d *Deployer) applyTraffic(service *run.Service) {
+	if len(d.traffic) == 0 {
+		return
+	}
+	targets := make([]*run.TrafficTarget, 0, len(d.traffic))
+	for _, target := range d.traffic {
+		targets = append(targets, &run.TrafficTarget{
+			RevisionName:   target.RevisionName,
+			LatestRevision: target.LatestRevision,
+			Percent:        target.Percent,
+		})
+	}
+	service.Spec.Traffic = targets
+}
+
+// applyDescription records the configured description on the service so the
+// Cloud console can display it.
+func (d *Deployer) applyDescription(service *run.Service) {
+	if d.description == "" {
+		return
+	}
+	if service.Metadata.Annotations == nil {
+		service.Metadata.Annotations = map[string]string{}
+	}
+	service.Metadata.Annotations[descriptionAnnotation] = d.description
+}
+
+// minScaleAnnotation and maxScaleAnnotation are the revision annotations Cloud
+// Run reads for autoscaling bounds.
+const (
+	minScaleAnnotation = "autoscaling.knative.dev/minScale"
+	maxScaleAnnotation = "autoscaling.knative.dev/maxScale"
+)
+
+// terminationGracePeriodAnnotation records the graceful shutdown window on the
+// revision template, and maxTerminationGracePeriod is the longest one Cloud Run
+// accepts.
+const (
+	terminationGracePeriodAnnotation = "run.googleapis.com/termination-grace-period-seconds"
+	maxTerminationGracePeriod        = 3600
+)
+
+// applyTerminationGracePeriod records how long an instance may take to shut
+// down after receiving SIGTERM.
+func (d *Deployer) applyTerminationGracePeriod(service *run.Service) {
+	if d.terminationGracePeriod == 0 {
+		return
+	}
+	if service.Spec.Template.Metadata == nil {
+		service.Spec.Template.Metadata = &run.ObjectMeta{}
+	}
+	if service.Spec.Template.Metadata.Annotations == nil {
+		service.Spec.Template.Metadata.Annotations = map[string]string{}
+	}
+	service.Spec.Template.Metadata.Annotations[terminationGracePeriodAnnotation] = strconv.Itoa(d.terminationGracePeriod)
+}
+
+// vpcConnectorAnnotation and vpcEgressAnnotation are the revision annotations
+// Cloud Run reads for Serverless VPC Access routing.
+const (
+	vpcConnectorAnnotation = "run.googleapis.com/vpc-access-connector"
+	vpcEgressAnnotation    = "run.googleapis.com/vpc-access-egress"
+)
+
+// applyServiceAccount sets the configured IAM service account on the revision
+// spec, overriding one named in the manifest. Empty leaves the manifest
+// untouched.
+func (d *Deployer) applyServiceAccount(service *run.Service) {
+	if d.serviceAccount == "" {
+		return
+	}
+	service.Spec.Template.Spec.ServiceAccountName = d.serviceAccount
+}
+
+// applyVPCAccess routes the revision's outbound traffic through the configured
+// Serverless VPC Access connector.
+func (d *Deployer) applyVPCAccess(service *run.Service) {
+	if d.vpcConnector == "" {
+		return
+	}
+	if service.Spec.Template.Metadata == nil {
+		service.Spec.Template.Metadata = &run.ObjectMeta{}
+	}
+	if service.Spec.Template.Metadata.Annotations == nil {
+		service.Spec.Template.Metadata.Annotations = map[string]string{}
+	}
+	service.Spec.Template.Metadata.Annotations[vpcConnectorAnnotation] = d.vpcConnector
+	if d.vpcEgress != "" {
+		service.Spec.Template.Metadata.Annotations[vpcEgressAnnotation] = d.vpcEgress
+	}
+}
+
+// applyScaling records the configured concurrency and autoscaling bounds on the
+// revision template. The values usually differ per environment and are patched
+// in through profiles.
+func (d *Deployer) applyScaling(service *run.Service) {
+	if d.containerConcurrency > 0 {
+		service.Spec.Template.Spec.ContainerConcurrency = int64(d.containerConcurrency)
+	}
+	if d.minInstances == 0 && d.maxInstances == 0 {
+		return
+	}
+	if service.Spec.Template.Metadata == nil {
+		service.Spec.Template.Metadata = &run.ObjectMeta{}
+	}
+	if service.Spec.Template.Metadata.Annotations == nil {
+		service.Spec.Template.Metadata.Annotations = map[string]string{}
+	}
+	if d.minInstances > 0 {
+		service.Spec.Template.Metadata.Annotations[minScaleAnnotation] = strconv.Itoa(d.minInstances)
+	}
+	if d.maxInstances > 0 {
+		service.Spec.Template.Metadata.Annotations[maxScaleAnnotation] = strconv.Itoa(d.maxInstances)
+	}
+}
+
+// applyStartupProbe sets the configured startup probe on every container of
+// the service, so Cloud Run knows when a container has started. Probes from
+// the manifest are replaced.
+func (d *Deployer) applyStartupProbe(service *run.Service) {
+	if d.startupProbe == nil {
+		return
+	}
+	for _, container := range service.Spec.Template.Spec.Containers {
+		probe := &run.Probe{
+			InitialDelaySeconds: int64(d.startupProbe.InitialDelaySeconds),
+			TimeoutSeconds:      int64(d.startupProbe.TimeoutSeconds),
+			PeriodSeconds:       int64(d.startupProbe.PeriodSeconds),
+			FailureThreshold:    int64(d.startupProbe.FailureThreshold),
+		}
+		if d.startupProbe.HTTPPath != "" {
+			probe.HttpGet = &run.HTTPGetAction{Path: d.startupProbe.HTTPPath}
+		} else {
+			probe.TcpSocket = &run.TCPSocketAction{}
+		}
+		container.StartupProbe = probe
+	}
+}
+
+// http2PortName is the container port name Cloud Run recognizes as enabling
+// end-to-end HTTP/2.
+const http2PortName = "h2c"
+
+// applyHTTP2 names the first container's port h2c so Cloud Run serves the
+// revision with end-to-end HTTP/2.
+func (d *Deployer) applyHTTP2(service *run.Service) {
+	if !d.useHTTP2 {
+		return
+	}
+	if len(service.Spec.Template.Spec.Containers) == 0 {
+		return
+	}
+	container := service.Spec.Template.Spec.Containers[0]
+	if len(container.Ports) == 0 {
+		container.Ports = []*run.ContainerPort{{Name: http2PortName}}
+		return
+	}
+	container.Ports[0].Name = http2PortName
+}
+
+// applyLaunchStage records the required launch stage on the service so Cloud Run
+// allows the pre-GA features the config depends on.
+func (d *Deployer) applyLaunchStage(service *run.Service) {
+	if d.launchStage == "" {
+		return
+	}
+	if service.Metadata.Annotations == nil {
+		service.Metadata.Annotations = map[string]string{}
+	}
+	service.Metadata.Annotations[launchStageAnnotation] = d.launchStage
+}
+
+// applyChangeCause records the reason for the deploy on the revision template.
+func (d *Deployer) applyChangeCause(service *run.Service) {
+	if d.changeCause == "" {
+		return
+	}
+	if service.Spec.Template.Metadata == nil {
+		service.Spec.Template.Metadata = &run.ObjectMeta{}
+	}
+	if service.Spec.Template.Metadata.Annotations == nil {
+		service.Spec.Template.Metadata.Annotations = map[string]string{}
+	}
+	service.Spec.Template.Metadata.Annotations[changeCauseAnnotation] = d.changeCause
+}
+
+// pinImageDigests annotates the revision with the digest of every container image
+// that carries one, so the exact deployed bits are recorded even when the image is
+// referenced by tag.
+func (d *Deployer) pinImageDigests(service *run.Service) {
+	if !d.pinDigest {
+		return
+	}
+	digests := make(map[string]string)
+	for _, container := range service.Spec.Template.Spec.Containers {
+		ref, err := docker.ParseReference(container.Image)
+		if err != nil || ref.Digest == "" {
+			continue
+		}
+		digests[ref.BaseName] = ref.Digest
+	}
+	if len(digests) == 0 {
+		return
+	}
+	annotation, err := json.Marshal(digests)
+	if err != nil {
+		return
+	}
+	if service.Spec.Template.Metadata == nil {
+		service.Spec.Template.Metadata = &run.ObjectMeta{}
+	}
+	if service.Spec.Template.Metadata.Annotations == nil {
+		service.Spec.Template.Metadata.Annotations = map[string]string{}
+	}
+	service.Spec.Template.Metadata.Annotations[imageDigestsAnnotation] = string(annotation)
+}
+
+// applyContainerDependencies sets the container startup order configured in the
+// Skaffold deploy config on the Service's revision template. Every container
+// referenced in the dependency map must exist in the template.
+func (d *Deployer) applyContainerDependencies(service *run.Service) error {
+	if len(d.containerDependencies) == 0 {
+		return nil
+	}
+	names := make(map[string]bool)
+	for _, container := range service.Spec.Template.Spec.Containers {
+		names[container.Name] = true
+	}
+	for container, deps := range d.containerDependencies {
+		for _, name := range append([]string{container}, deps...) {
+			if !names[name] {
+				return sErrors.NewError(fmt.Errorf("invalid container dependency: no container named %q in Cloud Run Service %q", name, service.Metadata.Name), &proto.ActionableErr{
+					Message: fmt.Sprintf("containerDependencies references container %q which is not defined in the Service manifest", name),
+					ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+				})
+			}
+		}
+	}
+	deps, err := json.Marshal(d.containerDependencies)
+	if err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to marshal container dependencies: %w", err), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+		})
+	}
+	if service.Spec.Template.Metadata == nil {
+		service.Spec.Template.Metadata = &run.ObjectMeta{}
+	}
+	if service.Spec.Template.Metadata.Annotations == nil {
+		service.Spec.Template.Metadata.Annotations = map[string]string{}
+	}
+	service.Spec.Template.Metadata.Annotations[containerDependenciesAnnotation] = string(deps)
+	return nil
+}
+
 func (d *Deployer) deployJob(crclient *run.APIService, manifest []byte, out io.Writer) (*RunResourceName, error) {
 	job := &run.Job{}
 	if err := k8syaml.Unmarshal(manifest, job); err != nil {
@@ -295,6 +1828,12 @@ func (d *Deployer) deployJob(crclient *run.APIService, manifest []byte, out io.W
 		Job:     job.Metadata.Name,
 	}
 	output.Default.Fprintln(out, "Deploying Cloud Run service:\n\t", job.Metadata.Name)
+	// the full job config is noisy, so only echo it at verbose/debug logging levels.
+	if isVerbose() {
+		if b, err := k8syaml.Marshal(job); err == nil {
+			output.Blue.Fprintf(out, "%s\n", b)
+		}
+	}
 	parent := fmt.Sprintf("namespaces/%s", job.Metadata.Namespace)
 
 	sName := resName.String()
@@ -311,10 +1850,16 @@ func (d *Deployer) deployJob(crclient *run.APIService, manifest []byte, out io.W
 		}
 		// This is a new service, we need to create it
 		createCall := crclient.Namespaces.Jobs.Create(parent, job)
-		_, err = createCall.Do()
+		err = d.withRetries(out, func() error {
+			_, err := createCall.Do()
+			return err
+		})
 	} else {
 		replaceCall := crclient.Namespaces.Jobs.ReplaceJob(sName, job)
-		_, err = replaceCall.Do()
+		err = d.withRetries(out, func() error {
+			_, err := replaceCall.Do()
+			return err
+		})
 	}
 	if err != nil {
 		return nil, sErrors.NewError(fmt.Errorf("error deploying Cloud Run Job: %s", err), &proto.ActionableErr{
@@ -322,19 +1867,41 @@ func (d *Deployer) deployJob(crclient *run.APIService, manifest []byte, out io.W
 			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
 		})
 	}
+	// the deploy is considered successful once the job definition is updated;
+	// starting an execution is opt-in via executeJob.
+	if d.executeJob {
+		output.Default.Fprintln(out, "Starting execution of Cloud Run Job:\n\t", job.Metadata.Name)
+		if _, err := crclient.Namespaces.Jobs.Run(sName, &run.RunJobRequest{}).Do(); err != nil {
+			return nil, sErrors.NewError(fmt.Errorf("error starting execution of Cloud Run Job: %s", err), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
+			})
+		}
+	}
 	return &resName, nil
 }
 
+// deleteRunService deletes every Cloud Run resource in the manifest list,
+// mirroring how Deploy fans out over the manifests of a config.
 func (d *Deployer) deleteRunService(ctx context.Context, out io.Writer, dryRun bool, manifests manifest.ManifestList) error {
-	if len(manifests) != 1 {
-		return sErrors.NewError(fmt.Errorf("unexpected manifest for Cloud Run"),
-			&proto.ActionableErr{
-				Message: "Cloud Run expected a single Service manifest.",
-				ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
-			})
+	for _, m := range manifests {
+		if err := d.deleteRunResource(ctx, out, dryRun, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Deployer) deleteRunResource(ctx context.Context, out io.Writer, dryRun bool, runManifest []byte) error {
+	// figure out which type we have: Cleanup must delete the resource type the
+	// manifest declares.
+	resource := &unstructured.Unstructured{}
+	if err := k8syaml.Unmarshal(runManifest, resource); err == nil &&
+		resource.GetAPIVersion() == "run.googleapis.com/v1" && resource.GetKind() == "Job" {
+		return d.deleteRunJob(ctx, out, dryRun, runManifest)
 	}
 	service := &run.Service{}
-	if err := k8syaml.Unmarshal(manifests[0], service); err != nil {
+	if err := k8syaml.Unmarshal(runManifest, service); err != nil {
 		return sErrors.NewError(fmt.Errorf("unable to unmarshal Cloud Run Service config"), &proto.ActionableErr{
 			Message: err.Error(),
 			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
@@ -360,20 +1927,147 @@ func (d *Deployer) deleteRunService(ctx context.Context, out io.Writer, dryRun b
 		output.Yellow.Fprintln(out, sName)
 		return nil
 	}
-	crclient, err := run.NewService(ctx, append(gcp.ClientOptions(ctx), d.clientOptions...)...)
+	crclient, err := d.client(ctx)
 	if err != nil {
-		return sErrors.NewError(fmt.Errorf("unable to create Cloud Run Client"), &proto.ActionableErr{
-			Message: err.Error(),
-			ErrCode: proto.StatusCode_DEPLOY_GET_CLOUD_RUN_CLIENT_ERR,
-		})
+		return err
 	}
 	delCall := crclient.Projects.Locations.Services.Delete(sName)
 	_, err = delCall.Do()
+	if err != nil && isDeletionProtectionErr(err) && d.forceDelete {
+		output.Default.Fprintln(out, "Disabling deletion protection for Cloud Run Service:\n\t", sName)
+		if err = disableDeletionProtection(crclient, sName); err != nil {
+			return err
+		}
+		_, err = crclient.Projects.Locations.Services.Delete(sName).Do()
+	}
 	if err != nil {
+		// a service that is already gone makes the delete a no-op success, so
+		// running `skaffold delete` twice does not fail the second run.
+		if gErr, ok := err.(*googleapi.Error); ok && gErr.Code == http.StatusNotFound {
+			output.Yellow.Fprintln(out, "Cloud Run Service already deleted:\n\t", sName)
+			return nil
+		}
+		if isDeletionProtectionErr(err) {
+			return sErrors.NewError(fmt.Errorf("unable to delete Cloud Run Service: deletion protection is enabled"), &proto.ActionableErr{
+				Message: "The Service has deletion protection enabled. Disable it in the Cloud console, or set `forceDelete: true` on the Cloud Run deploy config to let Skaffold disable it.",
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_DELETE_SERVICE_ERR,
+			})
+		}
 		return sErrors.NewError(fmt.Errorf("unable to delete Cloud Run Service"), &proto.ActionableErr{
 			Message: err.Error(),
 			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_DELETE_SERVICE_ERR,
 		})
 	}
+	if d.waitForDeletion {
+		return d.waitForServiceDeletion(ctx, out, crclient, sName)
+	}
+	return nil
+}
+
+// deleteRunJob deletes the Cloud Run Job the given manifest describes.
+func (d *Deployer) deleteRunJob(ctx context.Context, out io.Writer, dryRun bool, jobManifest []byte) error {
+	job := &run.Job{}
+	if err := k8syaml.Unmarshal(jobManifest, job); err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to unmarshal Cloud Run Job config"), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+		})
+	}
+	var projectID string
+	switch {
+	case d.Project != "":
+		projectID = d.Project
+	case job.Metadata.Namespace != "":
+		projectID = job.Metadata.Namespace
+	default:
+		// no project specified, we don't know what to delete.
+		return sErrors.NewError(fmt.Errorf("unable to determine Google Cloud Project"), &proto.ActionableErr{
+			Message: "No Google Cloud Project found in Cloud Run manifest or Skaffold Manifest.",
+			ErrCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
+		})
+	}
+	jName := fmt.Sprintf("namespaces/%s/jobs/%s", projectID, job.Metadata.Name)
+	if dryRun {
+		output.Yellow.Fprintln(out, jName)
+		return nil
+	}
+	crclient, err := d.client(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := crclient.Namespaces.Jobs.Delete(jName).Do(); err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to delete Cloud Run Job"), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_DELETE_SERVICE_ERR,
+		})
+	}
+	return nil
+}
+
+var (
+	deletePollPeriod  = 2 * time.Second
+	deletePollTimeout = 2 * time.Minute
+)
+
+// deletionProtectionAnnotation is the service annotation Cloud Run checks before
+// allowing a delete.
+const deletionProtectionAnnotation = "run.googleapis.com/deletion-protection"
+
+// isDeletionProtectionErr reports whether the delete was rejected because the
+// Service has deletion protection enabled.
+func isDeletionProtectionErr(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "deletion protection")
+}
+
+// disableDeletionProtection turns off deletion protection on the Service so a
+// following delete can succeed.
+func disableDeletionProtection(crclient *run.APIService, sName string) error {
+	service, err := crclient.Projects.Locations.Services.Get(sName).Do()
+	if err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to fetch Cloud Run Service to disable deletion protection"), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_GET_SERVICE_ERR,
+		})
+	}
+	if service.Metadata.Annotations == nil {
+		service.Metadata.Annotations = map[string]string{}
+	}
+	service.Metadata.Annotations[deletionProtectionAnnotation] = "false"
+	if _, err := crclient.Projects.Locations.Services.ReplaceService(sName, service).Do(); err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to disable deletion protection for Cloud Run Service"), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_UPDATE_SERVICE_ERR,
+		})
+	}
 	return nil
 }
+
+// waitForServiceDeletion polls the deleted Service until Cloud Run reports it gone,
+// so scripted teardown can rely on cleanup having completed.
+func (d *Deployer) waitForServiceDeletion(ctx context.Context, out io.Writer, crclient *run.APIService, sName string) error {
+	output.Default.Fprintln(out, "Waiting for Cloud Run Service to be deleted:\n\t", sName)
+	timeoutContext, cancel := context.WithTimeout(ctx, deletePollTimeout)
+	defer cancel()
+	ticker := time.NewTicker(deletePollPeriod)
+	defer ticker.Stop()
+	for {
+		_, err := crclient.Projects.Locations.Services.Get(sName).Do()
+		if gErr, ok := err.(*googleapi.Error); ok && gErr.Code == http.StatusNotFound {
+			return nil
+		}
+		if err != nil {
+			return sErrors.NewError(fmt.Errorf("error checking Cloud Run deletion state: %w", err), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_GET_SERVICE_ERR,
+			})
+		}
+		select {
+		case <-timeoutContext.Done():
+			return sErrors.NewError(fmt.Errorf("timed out waiting for Cloud Run Service %q to be deleted", sName), &proto.ActionableErr{
+				Message: fmt.Sprintf("Service %s was not deleted within %v", sName, deletePollTimeout),
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_DELETE_SERVICE_ERR,
+			})
+		case <-ticker.C:
+		}
+	}
+}
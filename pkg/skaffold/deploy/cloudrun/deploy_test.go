@@ -17,25 +17,36 @@ limitations under the License.
 package cloudrun
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	spec "github.com/opencontainers/image-spec/specs-go/v1"
 	"google.golang.org/api/option"
 	"google.golang.org/api/run/v1"
 	"google.golang.org/protobuf/testing/protocmp"
+	k8syaml "sigs.k8s.io/yaml"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/label"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
 	sErrors "github.com/GoogleContainerTools/skaffold/pkg/skaffold/errors"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/graph"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/manifest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/runcontext"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/proto/v1"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
@@ -44,6 +55,18 @@ const (
 	configName = "default"
 )
 
+// minimalServiceSpec returns the smallest revision template that passes service config
+// validation.
+func minimalServiceSpec() *run.ServiceSpec {
+	return &run.ServiceSpec{
+		Template: &run.RevisionTemplate{
+			Spec: &run.RevisionSpec{
+				Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+			},
+		},
+	}
+}
+
 func TestDeployService(tOuter *testing.T) {
 	tests := []struct {
 		description    string
@@ -65,6 +88,7 @@ func TestDeployService(tOuter *testing.T) {
 				Metadata: &run.ObjectMeta{
 					Name: "test-service",
 				},
+				Spec: minimalServiceSpec(),
 			},
 		},
 		{
@@ -79,6 +103,7 @@ func TestDeployService(tOuter *testing.T) {
 					Name:      "test-service",
 					Namespace: "my-project",
 				},
+				Spec: minimalServiceSpec(),
 			},
 		},
 		{
@@ -93,6 +118,7 @@ func TestDeployService(tOuter *testing.T) {
 					Name:      "test-service",
 					Namespace: "my-project",
 				},
+				Spec: minimalServiceSpec(),
 			},
 			errCode: proto.StatusCode_DEPLOY_CLOUD_RUN_GET_SERVICE_ERR,
 		},
@@ -105,6 +131,7 @@ func TestDeployService(tOuter *testing.T) {
 				Metadata: &run.ObjectMeta{
 					Name: "test-service",
 				},
+				Spec: minimalServiceSpec(),
 			},
 			errCode: proto.StatusCode_DEPLOY_READ_MANIFEST_ERR,
 		},
@@ -137,6 +164,7 @@ func TestDeployService(tOuter *testing.T) {
 				w.Write(b)
 			}))
 
+			t.Override(&headCommitMessage, func() string { return "" })
 			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: test.defaultProject, Region: test.region}, configName)
 			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
 			deployer.useGcpOptions = false
@@ -252,6 +280,7 @@ func TestDeployJob(tOuter *testing.T) {
 				w.Write(b)
 			}))
 
+			t.Override(&headCommitMessage, func() string { return "" })
 			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: test.defaultProject, Region: test.region}, configName)
 			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
 			deployer.useGcpOptions = false
@@ -274,6 +303,97 @@ func TestDeployJob(tOuter *testing.T) {
 	}
 }
 
+func TestDeployJobExecute(tOuter *testing.T) {
+	tests := []struct {
+		description        string
+		executeJob         bool
+		expectedExecutions int
+	}{
+		{
+			description: "deploy only updates the job definition by default",
+		},
+		{
+			description:        "executeJob starts an execution after the deploy",
+			executeJob:         true,
+			expectedExecutions: 1,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			var executions int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, ":run"):
+					executions++
+					w.Write([]byte("{}"))
+				case r.Method == "GET":
+					// job doesn't exist yet, so it gets created.
+					http.Error(w, "not found", http.StatusNotFound)
+				default:
+					body, _ := io.ReadAll(r.Body)
+					w.Write(body)
+				}
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:  "testProject",
+				Region:     "us-central1",
+				ExecuteJob: test.executeJob,
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Job{
+				ApiVersion: "run.googleapis.com/v1",
+				Kind:       "Job",
+				Metadata:   &run.ObjectMeta{Name: "test-job"},
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+
+			err := deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestsByConfig)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedExecutions, executions)
+		})
+	}
+}
+
+func TestCleanupJob(tOuter *testing.T) {
+	testutil.Run(tOuter, "cleanup deletes the job the manifest declares", func(t *testutil.T) {
+		var deletedPath string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "DELETE" {
+				http.Error(w, "unexpected method: "+r.Method, http.StatusBadRequest)
+				return
+			}
+			deletedPath = r.URL.Path
+			b, _ := json.Marshal(&run.Status{})
+			w.Write(b)
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "testProject",
+			Region:    "us-central1",
+		}, configName)
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Job{
+			ApiVersion: "run.googleapis.com/v1",
+			Kind:       "Job",
+			Metadata:   &run.ObjectMeta{Name: "test-job"},
+		})
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+		err := deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual("/apis/run.googleapis.com/v1/namespaces/testProject/jobs/test-job", deletedPath)
+	})
+}
+
 func TestDeployRewrites(tOuter *testing.T) {
 	tests := []struct {
 		description    string
@@ -300,6 +420,9 @@ func TestDeployRewrites(tOuter *testing.T) {
 								"skaffold.dev/run-id": "abc123",
 							},
 						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+						},
 					},
 				},
 			},
@@ -322,6 +445,9 @@ func TestDeployRewrites(tOuter *testing.T) {
 								"run-id": "abc123",
 							},
 						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+						},
 					},
 				},
 			},
@@ -335,6 +461,7 @@ func TestDeployRewrites(tOuter *testing.T) {
 					Name:      "test-service",
 					Namespace: "my-project",
 				},
+				Spec: minimalServiceSpec(),
 			},
 			defaultProject: "test-project",
 			region:         "us-central1",
@@ -345,6 +472,7 @@ func TestDeployRewrites(tOuter *testing.T) {
 					Name:      "test-service",
 					Namespace: "test-project",
 				},
+				Spec: minimalServiceSpec(),
 			},
 		},
 	}
@@ -378,6 +506,7 @@ func TestDeployRewrites(tOuter *testing.T) {
 				}
 				w.Write(b)
 			}))
+			t.Override(&headCommitMessage, func() string { return "" })
 			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: test.defaultProject, Region: test.region}, "")
 			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
 			deployer.useGcpOptions = false
@@ -393,90 +522,3099 @@ func TestDeployRewrites(tOuter *testing.T) {
 	}
 }
 
-func TestCleanup(tOuter *testing.T) {
+func TestValidateServiceConfig(tOuter *testing.T) {
 	tests := []struct {
-		description    string
-		toDelete       *run.Service
-		defaultProject string
-		region         string
-		expectedPath   string
-		httpErr        int
+		description string
+		toDeploy    *run.Service
+		shouldErr   bool
 	}{
 		{
-			description:    "test cleanup",
-			defaultProject: "testProject",
-			region:         "us-central1",
-			expectedPath:   "/v1/projects/testProject/locations/us-central1/services/test-service",
-			toDelete: &run.Service{
+			description: "valid service",
+			toDeploy: &run.Service{
+				Metadata: &run.ObjectMeta{Name: "test-service"},
+				Spec:     minimalServiceSpec(),
+			},
+		},
+		{
+			description: "service missing spec.template",
+			toDeploy: &run.Service{
+				Metadata: &run.ObjectMeta{Name: "test-service"},
+				Spec:     &run.ServiceSpec{},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "service missing containers",
+			toDeploy: &run.Service{
+				Metadata: &run.ObjectMeta{Name: "test-service"},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{},
+					},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "service missing metadata name",
+			toDeploy:    &run.Service{Metadata: &run.ObjectMeta{}},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			err := validateServiceConfig(test.toDeploy)
+			t.CheckError(test.shouldErr, err)
+			if test.shouldErr {
+				sErr := err.(sErrors.Error)
+				if sErr.StatusCode() != proto.StatusCode_DEPLOY_READ_MANIFEST_ERR {
+					t.Fatalf("Expected status code %v but got %v", proto.StatusCode_DEPLOY_READ_MANIFEST_ERR, sErr.StatusCode())
+				}
+			}
+		})
+	}
+}
+
+func TestDeployOutputVerbosity(tOuter *testing.T) {
+	tests := []struct {
+		description     string
+		verbose         bool
+		expectedOutputs []string
+		excludedOutputs []string
+	}{
+		{
+			description:     "concise output only prints the summary line",
+			verbose:         false,
+			expectedOutputs: []string{"Deploying Cloud Run service:\n\t test-service"},
+			excludedOutputs: []string{"serving.knative.dev/v1"},
+		},
+		{
+			description:     "verbose output includes the full service config",
+			verbose:         true,
+			expectedOutputs: []string{"Deploying Cloud Run service:\n\t test-service", "serving.knative.dev/v1"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			t.Override(&isVerbose, func() bool { return test.verbose })
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				b, _ := json.Marshal(&run.Service{})
+				w.Write(b)
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: "testProject", Region: "us-central1"}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: "test-service"},
+				Spec:       minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			var buf bytes.Buffer
+			err := deployer.Deploy(context.Background(), &buf, []graph.Artifact{}, manifestsByConfig)
+			if err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+			for _, expected := range test.expectedOutputs {
+				t.CheckContains(expected, buf.String())
+			}
+			for _, excluded := range test.excludedOutputs {
+				if strings.Contains(buf.String(), excluded) {
+					t.Errorf("expected output to not contain %q but got %q", excluded, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDeployMultiContainerImageSubstitution(tOuter *testing.T) {
+	tests := []struct {
+		description string
+		toDeploy    *run.Service
+		artifacts   []graph.Artifact
+		expected    *run.Service
+	}{
+		{
+			description: "images substituted into dependency-ordered containers",
+			toDeploy: &run.Service{
 				ApiVersion: "serving.knative.dev/v1",
 				Kind:       "Service",
 				Metadata: &run.ObjectMeta{
 					Name: "test-service",
 				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Metadata: &run.ObjectMeta{
+							Annotations: map[string]string{
+								"run.googleapis.com/container-dependencies": `{"app":["init"]}`,
+							},
+						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Name: "app", Image: "gcr.io/test-project/app"},
+								{Name: "init", Image: "gcr.io/test-project/init"},
+							},
+						},
+					},
+				},
+			},
+			artifacts: []graph.Artifact{
+				{ImageName: "gcr.io/test-project/app", Tag: "gcr.io/test-project/app:tag1"},
+				{ImageName: "gcr.io/test-project/init", Tag: "gcr.io/test-project/init:tag2"},
+			},
+			expected: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Metadata: &run.ObjectMeta{
+							Annotations: map[string]string{
+								"run.googleapis.com/container-dependencies": `{"app":["init"]}`,
+							},
+						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Name: "app", Image: "gcr.io/test-project/app:tag1"},
+								{Name: "init", Image: "gcr.io/test-project/init:tag2"},
+							},
+						},
+					},
+				},
 			},
 		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*test.expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: "test-project", Region: "us-central1"}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(test.toDeploy)
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			err := deployer.Deploy(context.Background(), os.Stderr, test.artifacts, manifestsByConfig)
+			if err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestContainerDependencies(tOuter *testing.T) {
+	tests := []struct {
+		description  string
+		dependencies map[string][]string
+		toDeploy     *run.Service
+		expected     *run.Service
+		shouldErr    bool
+	}{
 		{
-			description:    "test cleanup with specified project",
-			defaultProject: "testProject",
-			region:         "us-central1",
-			expectedPath:   "/v1/projects/testProject/locations/us-central1/services/test-service",
-			toDelete: &run.Service{
+			description:  "dependency annotation serialized onto revision template",
+			dependencies: map[string][]string{"app": {"init"}},
+			toDeploy: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Name: "app", Image: "gcr.io/test-project/app"},
+								{Name: "init", Image: "gcr.io/test-project/init"},
+							},
+						},
+					},
+				},
+			},
+			expected: &run.Service{
 				ApiVersion: "serving.knative.dev/v1",
 				Kind:       "Service",
 				Metadata: &run.ObjectMeta{
 					Name:      "test-service",
-					Namespace: "my-project",
+					Namespace: "test-project",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Metadata: &run.ObjectMeta{
+							Annotations: map[string]string{
+								"run.googleapis.com/container-dependencies": `{"app":["init"]}`,
+							},
+						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Name: "app", Image: "gcr.io/test-project/app"},
+								{Name: "init", Image: "gcr.io/test-project/init"},
+							},
+						},
+					},
 				},
 			},
 		},
 		{
-			description:    "test cleanup fails",
-			defaultProject: "testProject",
-			region:         "us-central1",
-			expectedPath:   "/v1/projects/testProject/locations/us-central1/services/test-service",
-			toDelete: &run.Service{
+			description:  "dependency on a container missing from the manifest",
+			dependencies: map[string][]string{"app": {"sidecar"}},
+			toDeploy: &run.Service{
 				ApiVersion: "serving.knative.dev/v1",
 				Kind:       "Service",
 				Metadata: &run.ObjectMeta{
 					Name: "test-service",
 				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Name: "app", Image: "gcr.io/test-project/app"},
+							},
+						},
+					},
+				},
 			},
-			httpErr: http.StatusUnauthorized,
+			shouldErr: true,
 		},
 	}
 	for _, test := range tests {
 		testutil.Run(tOuter, test.description, func(t *testutil.T) {
 			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if test.httpErr != 0 {
-					http.Error(w, "Expected http error", test.httpErr)
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
 					return
 				}
-				if r.URL.Path != test.expectedPath {
-					http.Error(w, "unexpected path: "+r.URL.Path, http.StatusNotFound)
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
 					return
 				}
-				response := &run.Status{}
-				b, err := json.Marshal(response)
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*test.expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
 				if err != nil {
 					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
 					return
 				}
 				w.Write(b)
 			}))
-			defer ts.Close()
-			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: test.defaultProject, Region: test.region}, configName)
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:             "test-project",
+				Region:                "us-central1",
+				ContainerDependencies: test.dependencies,
+			}, configName)
 			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
 			deployer.useGcpOptions = false
-			manifestListByConfig := manifest.NewManifestListByConfig()
-			manifest, _ := json.Marshal(test.toDelete)
-			manifests := [][]byte{manifest}
-			manifestListByConfig.Add(configName, manifests)
-			err := deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig)
-			if test.httpErr == 0 && err != nil {
-				t.Fatalf("Expected success but got err: %v", err)
-			} else if test.httpErr != 0 && err == nil {
-				t.Fatalf("Expected HTTP Error %s but got success", http.StatusText(test.httpErr))
-			}
+			m, _ := json.Marshal(test.toDeploy)
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig)
+			t.CheckError(test.shouldErr, err)
 		})
 	}
 }
+
+func TestPinDigest(tOuter *testing.T) {
+	digest := "sha256:e8e3a46b28d0397c3d17e2e3dd1dfa0b2bb4d0d6cbac29e2a34c4a5e3e66f5e1"
+	tests := []struct {
+		description string
+		pinDigest   bool
+		artifacts   []graph.Artifact
+		expected    *run.Service
+	}{
+		{
+			description: "digest annotation recorded when enabled",
+			pinDigest:   true,
+			artifacts: []graph.Artifact{
+				{ImageName: "gcr.io/test-project/app", Tag: "gcr.io/test-project/app:tag1@" + digest},
+			},
+			expected: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Metadata: &run.ObjectMeta{
+							Annotations: map[string]string{
+								"skaffold.dev/image-digests": fmt.Sprintf(`{"gcr.io/test-project/app":%q}`, digest),
+							},
+						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Image: "gcr.io/test-project/app:tag1@" + digest},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "no annotation when disabled",
+			artifacts: []graph.Artifact{
+				{ImageName: "gcr.io/test-project/app", Tag: "gcr.io/test-project/app:tag1@" + digest},
+			},
+			expected: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Image: "gcr.io/test-project/app:tag1@" + digest},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*test.expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID: "test-project",
+				Region:    "us-central1",
+				PinDigest: test.pinDigest,
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{
+								{Image: "gcr.io/test-project/app"},
+							},
+						},
+					},
+				},
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			err := deployer.Deploy(context.Background(), os.Stderr, test.artifacts, manifestsByConfig)
+			if err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestChangeCause(tOuter *testing.T) {
+	tests := []struct {
+		description       string
+		changeCause       string
+		headCommitMessage string
+		expectedCause     string
+	}{
+		{
+			description:   "change cause from config",
+			changeCause:   "canary rollout for experiment 42",
+			expectedCause: "canary rollout for experiment 42",
+		},
+		{
+			description:       "change cause defaults to the git commit subject",
+			headCommitMessage: "fix: roll back broken flag",
+			expectedCause:     "fix: roll back broken flag",
+		},
+		{
+			description:       "configured cause wins over git",
+			changeCause:       "manual hotfix",
+			headCommitMessage: "fix: roll back broken flag",
+			expectedCause:     "manual hotfix",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			expected := &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Metadata: &run.ObjectMeta{
+							Annotations: map[string]string{
+								"skaffold.dev/change-cause": test.expectedCause,
+							},
+						},
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+						},
+					},
+				},
+			}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return test.headCommitMessage })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:   "test-project",
+				Region:      "us-central1",
+				ChangeCause: test.changeCause,
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig)
+			if err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestServiceDescription(tOuter *testing.T) {
+	tests := []struct {
+		description         string
+		serviceDescription  string
+		expectedAnnotations map[string]string
+		expectedErr         string
+	}{
+		{
+			description:        "description recorded as a service annotation",
+			serviceDescription: "frontend for the billing team",
+			expectedAnnotations: map[string]string{
+				"run.googleapis.com/description": "frontend for the billing team",
+			},
+		},
+		{
+			description: "no description leaves the service untouched",
+		},
+		{
+			description:        "over-long description is rejected",
+			serviceDescription: strings.Repeat("x", 513),
+			expectedErr:        "description is too long",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			expected := &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:        "test-service",
+					Namespace:   "test-project",
+					Annotations: test.expectedAnnotations,
+				},
+				Spec: minimalServiceSpec(),
+			}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:   "test-project",
+				Region:      "us-central1",
+				Description: test.serviceDescription,
+			}, configName)
+			if test.expectedErr != "" {
+				t.CheckErrorContains(test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			err = deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig)
+			if err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientUserAgent(tOuter *testing.T) {
+	tests := []struct {
+		description string
+		userAgent   string
+		expected    string
+	}{
+		{
+			description: "default user-agent identifies skaffold",
+			expected:    "skaffold",
+		},
+		{
+			description: "user-agent can be overridden on the deployer",
+			userAgent:   "integration-test-agent",
+			expected:    "integration-test-agent",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			var gotUserAgent string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				gotUserAgent = r.Header.Get("User-Agent")
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(body)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID: "test-project",
+				Region:    "us-central1",
+			}, configName)
+			if test.userAgent != "" {
+				deployer.userAgent = test.userAgent
+			}
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			if err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig); err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+			if !strings.Contains(gotUserAgent, test.expected) {
+				t.Fatalf("expected user-agent containing %q but got %q", test.expected, gotUserAgent)
+			}
+		})
+	}
+}
+
+func TestLaunchStage(tOuter *testing.T) {
+	tests := []struct {
+		description string
+		launchStage string
+		expectedErr string
+	}{
+		{description: "ALPHA launch stage", launchStage: "ALPHA"},
+		{description: "BETA launch stage", launchStage: "BETA"},
+		{description: "GA launch stage", launchStage: "GA"},
+		{description: "invalid launch stage", launchStage: "PREVIEW", expectedErr: "invalid launchStage"},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			expected := &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+					Annotations: map[string]string{
+						"run.googleapis.com/launch-stage": test.launchStage,
+					},
+				},
+				Spec: minimalServiceSpec(),
+			}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:   "test-project",
+				Region:      "us-central1",
+				LaunchStage: test.launchStage,
+			}, configName)
+			if test.expectedErr != "" {
+				t.CheckErrorContains(test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			if err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig); err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestTemplateRemnantsRejected(tOuter *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		namespace   string
+		expectedErr string
+	}{
+		{
+			description: "unresolved go template in the service name",
+			name:        "test-service-{{.ENV}}",
+			expectedErr: "still contains a template marker",
+		},
+		{
+			description: "unresolved shell variable in the namespace",
+			name:        "test-service",
+			namespace:   "${PROJECT_ID}",
+			expectedErr: "still contains a template marker",
+		},
+		{
+			description: "fully resolved names deploy",
+			name:        "test-service",
+			namespace:   "test-project",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID: "test-project",
+				Region:    "us-central1",
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      test.name,
+					Namespace: test.namespace,
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig)
+
+			if test.expectedErr != "" {
+				t.CheckErrorContains(test.expectedErr, err)
+			} else {
+				t.CheckNoError(err)
+			}
+		})
+	}
+}
+
+func TestUseHTTP2(tOuter *testing.T) {
+	tests := []struct {
+		description   string
+		useHTTP2      bool
+		ports         []*run.ContainerPort
+		expectedPorts []*run.ContainerPort
+	}{
+		{
+			description:   "existing port is renamed h2c",
+			useHTTP2:      true,
+			ports:         []*run.ContainerPort{{Name: "http1", ContainerPort: 8080}},
+			expectedPorts: []*run.ContainerPort{{Name: "h2c", ContainerPort: 8080}},
+		},
+		{
+			description:   "h2c port is added when none is declared",
+			useHTTP2:      true,
+			expectedPorts: []*run.ContainerPort{{Name: "h2c"}},
+		},
+		{
+			description:   "ports are untouched when disabled",
+			ports:         []*run.ContainerPort{{Name: "http1", ContainerPort: 8080}},
+			expectedPorts: []*run.ContainerPort{{Name: "http1", ContainerPort: 8080}},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(test.expectedPorts, service.Spec.Template.Spec.Containers[0].Ports, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID: "test-project",
+				Region:    "us-central1",
+				UseHTTP2:  test.useHTTP2,
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{{
+								Image: "gcr.io/test/image",
+								Ports: test.ports,
+							}},
+						},
+					},
+				},
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			if err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig); err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeployEnvPrecedence(tOuter *testing.T) {
+	testutil.Run(tOuter, "env sources merge with documented precedence", func(t *testutil.T) {
+		envFile := t.NewTempDir().Write(".env", "C=from-file\nD=from-file\nE=from-file\n").Path(".env")
+		expectedEnv := []*run.EnvVar{
+			{Name: "A", Value: "from-manifest"},
+			{Name: "B", ValueFrom: &run.EnvVarSource{SecretKeyRef: &run.SecretKeySelector{Name: "b-secret", Key: "latest"}}},
+			{Name: "C", Value: "from-file"},
+			{Name: "D", Value: "from-env"},
+			{Name: "E", Value: "from-file"},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				http.Error(w, "want to return empty default", http.StatusNotFound)
+				return
+			}
+			var service run.Service
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if err = json.Unmarshal(body, &service); err != nil {
+				http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if diff := cmp.Diff(expectedEnv, service.Spec.Template.Spec.Containers[0].Env, protocmp.Transform()); diff != "" {
+				http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+				return
+			}
+			b, err := json.Marshal(service)
+			if err != nil {
+				http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(b)
+		}))
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+			Env:       map[string]string{"D": "from-env"},
+			EnvFile:   envFile,
+			SecretEnv: map[string]string{"B": "b-secret", "C": "c-secret:2", "D": "d-secret:3"},
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata: &run.ObjectMeta{
+				Name: "test-service",
+			},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{
+							Image: "gcr.io/test/image",
+							Env: []*run.EnvVar{
+								{Name: "A", Value: "from-manifest"},
+								{Name: "B", Value: "from-manifest"},
+								{Name: "C", Value: "from-manifest"},
+								{Name: "D", Value: "from-manifest"},
+							},
+						}},
+					},
+				},
+			},
+		})
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{m})
+		if err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig); err != nil {
+			t.Fatalf("Expected success but got err: %v", err)
+		}
+	})
+
+	testutil.Run(tOuter, "invalid env file is rejected", func(t *testutil.T) {
+		envFile := t.NewTempDir().Write(".env", "not a variable\n").Path(".env")
+		t.Override(&headCommitMessage, func() string { return "" })
+		_, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+			EnvFile:   envFile,
+		}, configName)
+
+		t.CheckErrorContains("unable to read Cloud Run env file", err)
+	})
+}
+
+func TestEnvFileReloadedOnRedeploy(tOuter *testing.T) {
+	testutil.Run(tOuter, "a redeploy picks up env file edits", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write(".env", "GREETING=first\n")
+		envFile := tmpDir.Path(".env")
+		var deployed run.Service
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				http.Error(w, "not found", http.StatusNotFound)
+			default:
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &deployed)
+				w.Write(body)
+			}
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+			EnvFile:   envFile,
+		}, configName, WithClientOptions(option.WithEndpoint(ts.URL), option.WithoutAuthentication()))
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+					},
+				},
+			},
+		})
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{m})
+
+		t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig))
+		t.CheckDeepEqual([]*run.EnvVar{{Name: "GREETING", Value: "first"}}, deployed.Spec.Template.Spec.Containers[0].Env)
+
+		// the dev loop watches the env file and redeploys on change; the
+		// second deploy must carry the edited values, not the cached ones.
+		tmpDir.Write(".env", "GREETING=second\n")
+
+		t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig))
+		t.CheckDeepEqual([]*run.EnvVar{{Name: "GREETING", Value: "second"}}, deployed.Spec.Template.Spec.Containers[0].Env)
+	})
+}
+
+func TestCleanupWaitForDeletion(tOuter *testing.T) {
+	tests := []struct {
+		description     string
+		presentGets     int
+		waitForDeletion bool
+		expectedGets    int
+	}{
+		{
+			description:     "cleanup waits until the service is gone",
+			presentGets:     2,
+			waitForDeletion: true,
+			expectedGets:    3,
+		},
+		{
+			description: "cleanup returns immediately when wait is disabled",
+			presentGets: 2,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			t.Override(&deletePollPeriod, time.Millisecond)
+			var gets int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					gets++
+					if gets <= test.presentGets {
+						b, _ := json.Marshal(&run.Service{Metadata: &run.ObjectMeta{Name: "test-service"}})
+						w.Write(b)
+						return
+					}
+					http.Error(w, "not found", http.StatusNotFound)
+					return
+				}
+				b, _ := json.Marshal(&run.Status{})
+				w.Write(b)
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:       "testProject",
+				Region:          "us-central1",
+				WaitForDeletion: test.waitForDeletion,
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: "test-service"},
+			})
+			manifestListByConfig := manifest.NewManifestListByConfig()
+			manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+			err := deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedGets, gets)
+		})
+	}
+}
+
+func TestCleanupDeletionProtection(tOuter *testing.T) {
+	tests := []struct {
+		description     string
+		forceDelete     bool
+		expectedErr     string
+		expectedDeletes int
+	}{
+		{
+			description:     "deletion protection surfaces a specific error",
+			expectedErr:     "The Service has deletion protection enabled",
+			expectedDeletes: 1,
+		},
+		{
+			description:     "forceDelete disables protection and retries the delete",
+			forceDelete:     true,
+			expectedDeletes: 2,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			var deletes int
+			var protectionDisabled bool
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "DELETE":
+					deletes++
+					if !protectionDisabled {
+						http.Error(w, "FAILED_PRECONDITION: deletion protection is enabled for the Service", http.StatusBadRequest)
+						return
+					}
+					b, _ := json.Marshal(&run.Status{})
+					w.Write(b)
+				case "GET":
+					b, _ := json.Marshal(&run.Service{Metadata: &run.ObjectMeta{Name: "test-service"}})
+					w.Write(b)
+				case "PUT":
+					var service run.Service
+					body, _ := io.ReadAll(r.Body)
+					if err := json.Unmarshal(body, &service); err != nil {
+						http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+						return
+					}
+					if service.Metadata.Annotations["run.googleapis.com/deletion-protection"] != "false" {
+						http.Error(w, "expected deletion protection to be disabled", http.StatusBadRequest)
+						return
+					}
+					protectionDisabled = true
+					w.Write(body)
+				}
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:   "testProject",
+				Region:      "us-central1",
+				ForceDelete: test.forceDelete,
+			}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: "test-service"},
+			})
+			manifestListByConfig := manifest.NewManifestListByConfig()
+			manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+			err := deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig)
+
+			if test.expectedErr != "" {
+				t.CheckErrorContains(test.expectedErr, err)
+			} else {
+				t.CheckNoError(err)
+			}
+			t.CheckDeepEqual(test.expectedDeletes, deletes)
+		})
+	}
+}
+
+func TestCredentialsFile(tOuter *testing.T) {
+	testutil.Run(tOuter, "credentials file forwarded as a client option", func(t *testutil.T) {
+		credsFile := t.NewTempDir().Touch("creds.json").Path("creds.json")
+
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:       "testProject",
+			Region:          "us-central1",
+			CredentialsFile: credsFile,
+		}, configName)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(1, len(deployer.clientOptions))
+		t.CheckDeepEqual(true, reflect.DeepEqual(option.WithCredentialsFile(credsFile), deployer.clientOptions[0]))
+	})
+
+	testutil.Run(tOuter, "missing credentials file is rejected", func(t *testutil.T) {
+		_, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:       "testProject",
+			Region:          "us-central1",
+			CredentialsFile: "does-not-exist.json",
+		}, configName)
+
+		t.CheckErrorContains("unable to read Cloud Run credentials file", err)
+	})
+}
+
+func TestCleanup(tOuter *testing.T) {
+	tests := []struct {
+		description    string
+		toDelete       *run.Service
+		defaultProject string
+		region         string
+		expectedPath   string
+		httpErr        int
+	}{
+		{
+			description:    "test cleanup",
+			defaultProject: "testProject",
+			region:         "us-central1",
+			expectedPath:   "/v1/projects/testProject/locations/us-central1/services/test-service",
+			toDelete: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+			},
+		},
+		{
+			description:    "test cleanup with specified project",
+			defaultProject: "testProject",
+			region:         "us-central1",
+			expectedPath:   "/v1/projects/testProject/locations/us-central1/services/test-service",
+			toDelete: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "my-project",
+				},
+			},
+		},
+		{
+			description:    "test cleanup fails",
+			defaultProject: "testProject",
+			region:         "us-central1",
+			expectedPath:   "/v1/projects/testProject/locations/us-central1/services/test-service",
+			toDelete: &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+			},
+			httpErr: http.StatusUnauthorized,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.httpErr != 0 {
+					http.Error(w, "Expected http error", test.httpErr)
+					return
+				}
+				if r.URL.Path != test.expectedPath {
+					http.Error(w, "unexpected path: "+r.URL.Path, http.StatusNotFound)
+					return
+				}
+				response := &run.Status{}
+				b, err := json.Marshal(response)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: test.defaultProject, Region: test.region}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			manifestListByConfig := manifest.NewManifestListByConfig()
+			manifest, _ := json.Marshal(test.toDelete)
+			manifests := [][]byte{manifest}
+			manifestListByConfig.Add(configName, manifests)
+			err := deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig)
+			if test.httpErr == 0 && err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			} else if test.httpErr != 0 && err == nil {
+				t.Fatalf("Expected HTTP Error %s but got success", http.StatusText(test.httpErr))
+			}
+		})
+	}
+}
+
+func TestCleanupAlreadyDeleted(tOuter *testing.T) {
+	testutil.Run(tOuter, "deleting an absent service is a no-op success", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "not found", http.StatusNotFound)
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: "testProject", Region: "us-central1"}, configName)
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+		})
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+		var buf bytes.Buffer
+		t.CheckNoError(deployer.Cleanup(context.Background(), &buf, false, manifestListByConfig))
+		t.CheckContains("already deleted", buf.String())
+	})
+}
+
+func TestServiceAccount(tOuter *testing.T) {
+	tests := []struct {
+		description     string
+		serviceAccount  string
+		manifestAccount string
+		expected        string
+	}{
+		{
+			description:     "configured account overrides the manifest",
+			serviceAccount:  "app@test-project.iam.gserviceaccount.com",
+			manifestAccount: "manifest@test-project.iam.gserviceaccount.com",
+			expected:        "app@test-project.iam.gserviceaccount.com",
+		},
+		{
+			description:     "empty config leaves the manifest untouched",
+			manifestAccount: "manifest@test-project.iam.gserviceaccount.com",
+			expected:        "manifest@test-project.iam.gserviceaccount.com",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			var deployed run.Service
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					http.Error(w, "not found", http.StatusNotFound)
+				default:
+					body, _ := io.ReadAll(r.Body)
+					json.Unmarshal(body, &deployed)
+					w.Write(body)
+				}
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:      "testProject",
+				Region:         "us-central1",
+				ServiceAccount: test.serviceAccount,
+			}, configName, WithClientOptions(option.WithEndpoint(ts.URL), option.WithoutAuthentication()))
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: "test-service"},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							ServiceAccountName: test.manifestAccount,
+							Containers:         []*run.Container{{Image: "gcr.io/test/image"}},
+						},
+					},
+				},
+			})
+			manifestListByConfig := manifest.NewManifestListByConfig()
+			manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+			t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestListByConfig))
+			t.CheckDeepEqual(test.expected, deployed.Spec.Template.Spec.ServiceAccountName)
+		})
+	}
+}
+
+func TestVPCAccess(tOuter *testing.T) {
+	tests := []struct {
+		description         string
+		vpcConnector        string
+		vpcEgress           string
+		expectedAnnotations map[string]string
+		expectedErr         string
+	}{
+		{
+			description:  "connector with all-traffic egress",
+			vpcConnector: "test-connector",
+			vpcEgress:    "all-traffic",
+			expectedAnnotations: map[string]string{
+				"run.googleapis.com/vpc-access-connector": "test-connector",
+				"run.googleapis.com/vpc-access-egress":    "all-traffic",
+			},
+		},
+		{
+			description:  "connector with private-ranges-only egress",
+			vpcConnector: "test-connector",
+			vpcEgress:    "private-ranges-only",
+			expectedAnnotations: map[string]string{
+				"run.googleapis.com/vpc-access-connector": "test-connector",
+				"run.googleapis.com/vpc-access-egress":    "private-ranges-only",
+			},
+		},
+		{
+			description:  "connector without egress",
+			vpcConnector: "test-connector",
+			expectedAnnotations: map[string]string{
+				"run.googleapis.com/vpc-access-connector": "test-connector",
+			},
+		},
+		{
+			description: "egress without a connector",
+			vpcEgress:   "all-traffic",
+			expectedErr: "requires a vpcConnector",
+		},
+		{
+			description:  "invalid egress value",
+			vpcConnector: "test-connector",
+			vpcEgress:    "public-traffic",
+			expectedErr:  "invalid vpcEgress",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			expected := &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+				},
+				Spec: minimalServiceSpec(),
+			}
+			expected.Spec.Template.Metadata = &run.ObjectMeta{Annotations: test.expectedAnnotations}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:    "test-project",
+				Region:       "us-central1",
+				VPCConnector: test.vpcConnector,
+				VPCEgress:    test.vpcEgress,
+			}, configName)
+			if test.expectedErr != "" {
+				t.CheckErrorContains(test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			if err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig); err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestRender(tOuter *testing.T) {
+	serviceManifest := func() []byte {
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata: &run.ObjectMeta{
+				Name: "test-service",
+			},
+			Spec: minimalServiceSpec(),
+		})
+		return m
+	}
+	artifacts := []graph.Artifact{
+		{ImageName: "gcr.io/test/image", Tag: "gcr.io/test/image:tag123"},
+	}
+	testutil.Run(tOuter, "renders the processed service without contacting the API", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:   "test-project",
+			Region:      "us-central1",
+			LaunchStage: "BETA",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{serviceManifest()})
+		var buf bytes.Buffer
+
+		if err := deployer.Render(context.Background(), &buf, artifacts, true, "", manifestsByConfig); err != nil {
+			t.Fatalf("Expected success but got err: %v", err)
+		}
+
+		rendered := buf.String()
+		t.CheckContains("gcr.io/test/image:tag123", rendered)
+		t.CheckContains("test-project", rendered)
+		t.CheckContains("run.googleapis.com/launch-stage", rendered)
+	})
+	testutil.Run(tOuter, "writes the rendered service to the given file", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{serviceManifest()})
+		path := t.NewTempDir().Path("service.yaml")
+
+		if err := deployer.Render(context.Background(), os.Stderr, artifacts, true, path, manifestsByConfig); err != nil {
+			t.Fatalf("Expected success but got err: %v", err)
+		}
+
+		rendered, err := os.ReadFile(path)
+		t.CheckNoError(err)
+		t.CheckContains("test-service", string(rendered))
+		t.CheckContains("gcr.io/test/image:tag123", string(rendered))
+	})
+}
+
+func TestValidateImageRegistries(t *testing.T) {
+	tests := []struct {
+		description string
+		tag         string
+		expectedErr string
+	}{
+		{
+			description: "Artifact Registry image is accepted",
+			tag:         "us-docker.pkg.dev/test-project/repo/image:tag",
+		},
+		{
+			description: "Container Registry image is accepted",
+			tag:         "gcr.io/test-project/image:tag",
+		},
+		{
+			description: "regional Container Registry image is accepted",
+			tag:         "eu.gcr.io/test-project/image:tag",
+		},
+		{
+			description: "Docker Hub image is rejected with the host named",
+			tag:         "docker.io/library/nginx:latest",
+			expectedErr: `hosted on "index.docker.io"`,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			err := validateImageRegistries([]graph.Artifact{{ImageName: test.tag, Tag: test.tag}})
+
+			if test.expectedErr == "" {
+				t.CheckNoError(err)
+			} else {
+				t.CheckErrorContains(test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTerminationGracePeriod(tOuter *testing.T) {
+	tests := []struct {
+		description  string
+		gracePeriod  int
+		expectedErr  string
+		expectedMeta *run.ObjectMeta
+	}{
+		{
+			description: "grace period serializes onto the revision template",
+			gracePeriod: 120,
+			expectedMeta: &run.ObjectMeta{
+				Annotations: map[string]string{
+					"run.googleapis.com/termination-grace-period-seconds": "120",
+				},
+			},
+		},
+		{
+			description: "zero leaves the revision template untouched",
+			gracePeriod: 0,
+		},
+		{
+			description: "negative grace period is rejected",
+			gracePeriod: -1,
+			expectedErr: "terminationGracePeriodSeconds",
+		},
+		{
+			description: "grace period beyond the Cloud Run limit is rejected",
+			gracePeriod: 3601,
+			expectedErr: "must be between 0 and 3600",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			expected := &run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name:      "test-service",
+					Namespace: "test-project",
+				},
+				Spec: minimalServiceSpec(),
+			}
+			expected.Spec.Template.Metadata = test.expectedMeta
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "want to return empty default", http.StatusNotFound)
+					return
+				}
+				var service run.Service
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				if err = json.Unmarshal(body, &service); err != nil {
+					http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				if diff := cmp.Diff(*expected, service, protocmp.Transform()); diff != "" {
+					http.Error(w, "Expected equal but got diff "+diff, http.StatusBadRequest)
+					return
+				}
+				b, err := json.Marshal(service)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+			}))
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:                     "test-project",
+				Region:                        "us-central1",
+				TerminationGracePeriodSeconds: test.gracePeriod,
+			}, configName)
+			if test.expectedErr != "" {
+				t.CheckErrorContains(test.expectedErr, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata: &run.ObjectMeta{
+					Name: "test-service",
+				},
+				Spec: minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+			if err := deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig); err != nil {
+				t.Fatalf("Expected success but got err: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	tests := []struct {
+		description string
+		labels      map[string]string
+		expectedErr string
+	}{
+		{
+			description: "conforming label is accepted",
+			labels:      map[string]string{"env": "prod-1"},
+		},
+		{
+			description: "uppercase key is rejected",
+			labels:      map[string]string{"Env": "prod"},
+			expectedErr: `label key "Env" is not a valid Cloud Run label`,
+		},
+		{
+			description: "uppercase value is rejected",
+			labels:      map[string]string{"env": "Prod"},
+			expectedErr: `label value "Prod" for key "env" is not a valid Cloud Run label`,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			service := &run.Service{
+				Metadata: &run.ObjectMeta{
+					Name:   "test-service",
+					Labels: test.labels,
+				},
+				Spec: minimalServiceSpec(),
+			}
+
+			err := validateLabels(service)
+
+			if test.expectedErr == "" {
+				t.CheckNoError(err)
+			} else {
+				t.CheckErrorContains(test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestDeployServiceWithoutContainers(tOuter *testing.T) {
+	testutil.Run(tOuter, "service yaml omitting containers is rejected before indexing", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m := []byte(`apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: test-service
+spec:
+  template:
+    spec: {}
+`)
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{m})
+
+		err = deployer.Deploy(context.Background(), os.Stderr, nil, manifestsByConfig)
+
+		t.CheckErrorContains("must define spec.template.spec.containers", err)
+		sErr := err.(sErrors.Error)
+		if sErr.StatusCode() != proto.StatusCode_DEPLOY_READ_MANIFEST_ERR {
+			t.Fatalf("Expected status code %v but got %v", proto.StatusCode_DEPLOY_READ_MANIFEST_ERR, sErr.StatusCode())
+		}
+	})
+}
+
+func TestRegionFallbackChain(tOuter *testing.T) {
+	tests := []struct {
+		description    string
+		region         string
+		gcloudRegion   string
+		defaultRegion  string
+		expectedRegion string
+	}{
+		{
+			description:    "configured region wins over every fallback",
+			region:         "us-central1",
+			gcloudRegion:   "europe-west1",
+			defaultRegion:  "asia-east1",
+			expectedRegion: "us-central1",
+		},
+		{
+			description:    "gcloud configuration is used when the config has no region",
+			gcloudRegion:   "europe-west1",
+			defaultRegion:  "asia-east1",
+			expectedRegion: "europe-west1",
+		},
+		{
+			description:    "configured default is used when every other source is empty",
+			defaultRegion:  "asia-east1",
+			expectedRegion: "asia-east1",
+		},
+		{
+			description:    "no source leaves the region empty",
+			expectedRegion: "",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			t.Override(&headCommitMessage, func() string { return "" })
+			t.Override(&gcloudRunRegion, func() string { return test.gcloudRegion })
+
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:     "test-project",
+				Region:        test.region,
+				DefaultRegion: test.defaultRegion,
+			}, configName)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedRegion, deployer.Region)
+		})
+	}
+}
+
+func TestReadyConditionType(tOuter *testing.T) {
+	testutil.Run(tOuter, "invalid condition type is rejected", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		_, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:          "test-project",
+			Region:             "us-central1",
+			ReadyConditionType: "Bogus",
+		}, configName)
+		t.CheckErrorContains("invalid readyConditionType", err)
+	})
+	testutil.Run(tOuter, "monitor polls the configured condition", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:          "test-project",
+			Region:             "us-central1",
+			ReadyConditionType: "RoutesReady",
+		}, configName)
+		t.CheckNoError(err)
+		t.CheckDeepEqual("RoutesReady", deployer.getMonitor().readyConditionType)
+	})
+}
+
+func TestDefaultLabels(tOuter *testing.T) {
+	manifest := []byte(`
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: test-service
+  labels:
+    team: from-manifest
+spec:
+  template:
+    spec:
+      containers:
+      - image: gcr.io/test/image
+`)
+	testutil.Run(tOuter, "default labels merge beneath manifest and run-id labels", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, label.NewLabeller(true, nil, "test-run-id"), &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+			DefaultLabels: map[string]string{
+				"owner":  "platform",
+				"team":   "from-defaults",
+				"run-id": "from-defaults",
+			},
+		}, configName)
+		t.CheckNoError(err)
+
+		service, err := deployer.prepareService(manifest, nil)
+		t.CheckNoError(err)
+		t.CheckDeepEqual("platform", service.Metadata.Labels["owner"])
+		t.CheckDeepEqual("from-manifest", service.Metadata.Labels["team"])
+		t.CheckDeepEqual("test-run-id", service.Metadata.Labels["run-id"])
+		// the revision template carries the default labels too.
+		t.CheckDeepEqual("platform", service.Spec.Template.Metadata.Labels["owner"])
+	})
+	testutil.Run(tOuter, "default labels breaking GCP label rules are rejected", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		_, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:     "test-project",
+			Region:        "us-central1",
+			DefaultLabels: map[string]string{"Team": "platform"},
+		}, configName)
+		t.CheckErrorContains("invalid defaultLabels key", err)
+	})
+}
+
+func TestContainerLimit(tOuter *testing.T) {
+	overLimitManifest := func() []byte {
+		var containers []*run.Container
+		for i := 0; i < maxContainersPerService+1; i++ {
+			containers = append(containers, &run.Container{Image: fmt.Sprintf("gcr.io/test/image%d", i)})
+		}
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{Containers: containers},
+				},
+			},
+		})
+		return m
+	}
+	testutil.Run(tOuter, "over-limit container count warns by default", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		fakeWarner := &warnings.Collect{}
+		t.Override(&warnings.Printf, fakeWarner.Warnf)
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		t.CheckNoError(err)
+
+		_, err = deployer.prepareService(overLimitManifest(), nil)
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{
+			fmt.Sprintf("Cloud Run Service %q defines %d containers: managed Cloud Run allows at most %d per service",
+				"test-service", maxContainersPerService+1, maxContainersPerService),
+		}, fakeWarner.Warnings)
+	})
+	testutil.Run(tOuter, "over-limit container count fails with strictContainerLimit", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:            "test-project",
+			Region:               "us-central1",
+			StrictContainerLimit: true,
+		}, configName)
+		t.CheckNoError(err)
+
+		_, err = deployer.prepareService(overLimitManifest(), nil)
+		t.CheckErrorContains("managed Cloud Run allows at most", err)
+	})
+}
+
+func TestStartupProbe(tOuter *testing.T) {
+	manifest := []byte(`
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: test-service
+spec:
+  template:
+    spec:
+      containers:
+      - image: gcr.io/test/image
+`)
+	tests := []struct {
+		description         string
+		probe               *latest.CloudRunStartupProbe
+		expected            *run.Probe
+		shouldErrConstructs bool
+	}{
+		{
+			description: "http probe with thresholds",
+			probe: &latest.CloudRunStartupProbe{
+				HTTPPath:            "/healthz",
+				InitialDelaySeconds: 5,
+				TimeoutSeconds:      2,
+				PeriodSeconds:       4,
+				FailureThreshold:    6,
+			},
+			expected: &run.Probe{
+				HttpGet:             &run.HTTPGetAction{Path: "/healthz"},
+				InitialDelaySeconds: 5,
+				TimeoutSeconds:      2,
+				PeriodSeconds:       4,
+				FailureThreshold:    6,
+			},
+		},
+		{
+			description: "probe without a path checks the container port",
+			probe:       &latest.CloudRunStartupProbe{FailureThreshold: 2},
+			expected: &run.Probe{
+				TcpSocket:        &run.TCPSocketAction{},
+				FailureThreshold: 2,
+			},
+		},
+		{
+			description:         "negative thresholds are rejected",
+			probe:               &latest.CloudRunStartupProbe{FailureThreshold: -1},
+			shouldErrConstructs: true,
+		},
+		{
+			description:         "negative periods are rejected",
+			probe:               &latest.CloudRunStartupProbe{PeriodSeconds: -3},
+			shouldErrConstructs: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:    "test-project",
+				Region:       "us-central1",
+				StartupProbe: test.probe,
+			}, configName)
+			if test.shouldErrConstructs {
+				t.CheckErrorContains("startupProbe", err)
+				return
+			}
+			t.CheckNoError(err)
+
+			service, err := deployer.prepareService(manifest, nil)
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expected, service.Spec.Template.Spec.Containers[0].StartupProbe)
+		})
+	}
+}
+
+func TestAnnotationMergeStrategy(tOuter *testing.T) {
+	manifest := []byte(`
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: test-service
+  annotations:
+    custom-note: from-manifest
+    run.googleapis.com/description: manifest description
+spec:
+  template:
+    metadata:
+      annotations:
+        custom-revision: from-manifest
+    spec:
+      containers:
+      - image: gcr.io/test/image
+`)
+	tests := []struct {
+		description         string
+		strategy            string
+		expectedService     map[string]string
+		expectedRevision    map[string]string
+		shouldErrConstructs bool
+	}{
+		{
+			description: "merge keeps manifest annotations and overwrites conflicts",
+			strategy:    "merge",
+			expectedService: map[string]string{
+				"custom-note":                    "from-manifest",
+				"run.googleapis.com/description": "configured description",
+			},
+			expectedRevision: map[string]string{
+				"custom-revision":           "from-manifest",
+				"skaffold.dev/change-cause": "deploy for test",
+			},
+		},
+		{
+			description: "replace discards manifest annotations",
+			strategy:    "replace",
+			expectedService: map[string]string{
+				"run.googleapis.com/description": "configured description",
+			},
+			expectedRevision: map[string]string{
+				"skaffold.dev/change-cause": "deploy for test",
+			},
+		},
+		{
+			description:         "unknown strategies are rejected",
+			strategy:            "overlay",
+			shouldErrConstructs: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:               "test-project",
+				Region:                  "us-central1",
+				Description:             "configured description",
+				ChangeCause:             "deploy for test",
+				AnnotationMergeStrategy: test.strategy,
+			}, configName)
+			if test.shouldErrConstructs {
+				t.CheckErrorContains("annotationMergeStrategy", err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			service, err := deployer.prepareService(manifest, nil)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedService, service.Metadata.Annotations)
+			t.CheckDeepEqual(test.expectedRevision, service.Spec.Template.Metadata.Annotations)
+		})
+	}
+}
+
+func TestApplySkaffoldLabels(tOuter *testing.T) {
+	testutil.Run(tOuter, "labeller labels are sanitized and merged", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		labeller := label.NewLabeller(true, []string{"MyTeam=Platform Eng"}, "test-run-id")
+		deployer, err := NewDeployer(&runcontext.RunContext{}, labeller, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		service := &run.Service{
+			Metadata: &run.ObjectMeta{Name: "test-service", Labels: map[string]string{"existing": "kept"}},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+					},
+				},
+			},
+		}
+		deployer.applySkaffoldLabels(service)
+		expected := map[string]string{
+			"existing": "kept",
+			"run-id":   "test-run-id",
+			"myteam":   "platform-eng",
+		}
+		t.CheckDeepEqual(expected, service.Metadata.Labels)
+		t.CheckDeepEqual(map[string]string{
+			"run-id": "test-run-id",
+			"myteam": "platform-eng",
+		}, service.Spec.Template.Metadata.Labels)
+		// the sanitized labels pass Cloud Run's validation.
+		t.CheckNoError(validateLabels(service))
+	})
+}
+
+func TestTrafficSplit(t *testing.T) {
+	tests := []struct {
+		description string
+		traffic     []latest.CloudRunTrafficTarget
+		expected    []*run.TrafficTarget
+		shouldErr   bool
+		errContains string
+	}{
+		{
+			description: "canary split between latest and a pinned revision",
+			traffic: []latest.CloudRunTrafficTarget{
+				{LatestRevision: true, Percent: 10},
+				{RevisionName: "test-service-00001", Percent: 90},
+			},
+			expected: []*run.TrafficTarget{
+				{LatestRevision: true, Percent: 10},
+				{RevisionName: "test-service-00001", Percent: 90},
+			},
+		},
+		{
+			description: "no traffic section leaves the spec untouched",
+		},
+		{
+			description: "percents must add up to 100",
+			traffic: []latest.CloudRunTrafficTarget{
+				{LatestRevision: true, Percent: 10},
+				{RevisionName: "test-service-00001", Percent: 80},
+			},
+			shouldErr:   true,
+			errContains: "must add up to 100",
+		},
+		{
+			description: "target must name a revision or latestRevision",
+			traffic: []latest.CloudRunTrafficTarget{
+				{Percent: 100},
+			},
+			shouldErr:   true,
+			errContains: "must name a revision",
+		},
+		{
+			description: "target cannot set both revisionName and latestRevision",
+			traffic: []latest.CloudRunTrafficTarget{
+				{RevisionName: "test-service-00001", LatestRevision: true, Percent: 100},
+			},
+			shouldErr:   true,
+			errContains: "cannot also set latestRevision",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID: "test-project",
+				Region:    "us-central1",
+				Traffic:   test.traffic,
+			}, configName)
+			if test.shouldErr {
+				t.CheckErrorContains(test.errContains, err)
+				return
+			}
+			t.CheckNoError(err)
+			service := &run.Service{
+				Metadata: &run.ObjectMeta{Name: "test-service"},
+				Spec:     &run.ServiceSpec{},
+			}
+			deployer.applyTraffic(service)
+			t.CheckDeepEqual(test.expected, service.Spec.Traffic, protocmp.Transform())
+		})
+	}
+}
+
+func TestDeployEnvPrefix(tOuter *testing.T) {
+	testutil.Run(tOuter, "prefixes are applied per container", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+			Env:       map[string]string{"PORT": "8080"},
+			SecretEnv: map[string]string{"TOKEN": "token-secret"},
+			EnvPrefix: map[string]string{"app": "APP_", "worker": "WORKER_"},
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		service := &run.Service{
+			Metadata: &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{
+							{Name: "app", Image: "gcr.io/test/app"},
+							{Name: "worker", Image: "gcr.io/test/worker"},
+							{Name: "extra", Image: "gcr.io/test/extra"},
+						},
+					},
+				},
+			},
+		}
+		deployer.applyEnv(service)
+		tokenFrom := &run.EnvVarSource{SecretKeyRef: &run.SecretKeySelector{Name: "token-secret", Key: "latest"}}
+		t.CheckDeepEqual([]*run.EnvVar{
+			{Name: "APP_TOKEN", ValueFrom: tokenFrom},
+			{Name: "APP_PORT", Value: "8080"},
+		}, service.Spec.Template.Spec.Containers[0].Env, protocmp.Transform())
+		t.CheckDeepEqual([]*run.EnvVar{
+			{Name: "WORKER_TOKEN", ValueFrom: tokenFrom},
+			{Name: "WORKER_PORT", Value: "8080"},
+		}, service.Spec.Template.Spec.Containers[1].Env, protocmp.Transform())
+		// containers without a prefix entry receive no injected environment.
+		t.CheckDeepEqual(0, len(service.Spec.Template.Spec.Containers[2].Env))
+	})
+}
+
+func TestUploadMissingImages(t *testing.T) {
+	tests := []struct {
+		description string
+		uploadRepo  string
+		artifacts   []graph.Artifact
+		expected    []graph.Artifact
+		copies      map[string]string
+	}{
+		{
+			description: "image outside a supported registry is copied to uploadRepo",
+			uploadRepo:  "us-docker.pkg.dev/test/repo",
+			artifacts:   []graph.Artifact{{ImageName: "nginx", Tag: "docker.io/library/nginx:latest"}},
+			expected:    []graph.Artifact{{ImageName: "nginx", Tag: "us-docker.pkg.dev/test/repo/nginx:latest"}},
+			copies:      map[string]string{"docker.io/library/nginx:latest": "us-docker.pkg.dev/test/repo/nginx:latest"},
+		},
+		{
+			description: "image already in a supported registry is left untouched",
+			uploadRepo:  "us-docker.pkg.dev/test/repo",
+			artifacts:   []graph.Artifact{{ImageName: "gcr.io/test/app", Tag: "gcr.io/test/app:tag1"}},
+			expected:    []graph.Artifact{{ImageName: "gcr.io/test/app", Tag: "gcr.io/test/app:tag1"}},
+			copies:      map[string]string{},
+		},
+		{
+			description: "no uploadRepo leaves artifacts untouched",
+			artifacts:   []graph.Artifact{{ImageName: "nginx", Tag: "docker.io/library/nginx:latest"}},
+			expected:    []graph.Artifact{{ImageName: "nginx", Tag: "docker.io/library/nginx:latest"}},
+			copies:      map[string]string{},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			copies := map[string]string{}
+			t.Override(&copyImageToRepo, func(src, target string, _ docker.Config) error {
+				copies[src] = target
+				return nil
+			})
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:  "test-project",
+				Region:     "us-central1",
+				UploadRepo: test.uploadRepo,
+			}, configName)
+			t.CheckNoError(err)
+			got, err := deployer.uploadMissingImages(io.Discard, test.artifacts)
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expected, got)
+			t.CheckDeepEqual(test.copies, copies)
+		})
+	}
+}
+
+func TestAPIEndpoint(tOuter *testing.T) {
+	testutil.Run(tOuter, "configured endpoint serves the deploy and delete clients", func(t *testutil.T) {
+		var requests int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			switch r.Method {
+			case "GET":
+				http.Error(w, "not found", http.StatusNotFound)
+			case "DELETE":
+				b, _ := json.Marshal(&run.Status{})
+				w.Write(b)
+			default:
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			}
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:   "testProject",
+			Region:      "us-central1",
+			APIEndpoint: ts.URL,
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+					},
+				},
+			},
+		})
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+		t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestListByConfig))
+		deploys := requests
+		t.CheckNoError(deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig))
+
+		if deploys == 0 {
+			t.Errorf("expected the deploy client to hit the configured endpoint")
+		}
+		if requests == deploys {
+			t.Errorf("expected the delete client to hit the configured endpoint")
+		}
+	})
+}
+
+func TestAppliedServiceFile(tOuter *testing.T) {
+	testutil.Run(tOuter, "deploy writes the applied service to the configured file", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				http.Error(w, "not found", http.StatusNotFound)
+			default:
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			}
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		appliedFile := t.NewTempDir().Path("applied-service.yaml")
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:          "testProject",
+			Region:             "us-central1",
+			AppliedServiceFile: appliedFile,
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{Image: "img"}},
+					},
+				},
+			},
+		})
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+		t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{{ImageName: "img", Tag: "gcr.io/test/img:tag"}}, manifestListByConfig))
+
+		b, err := os.ReadFile(appliedFile)
+		t.CheckNoError(err)
+		applied := &run.Service{}
+		t.CheckNoError(k8syaml.Unmarshal(b, applied))
+		t.CheckDeepEqual("test-service", applied.Metadata.Name)
+		// the file holds the service as applied, after image substitution.
+		t.CheckDeepEqual("gcr.io/test/img:tag", applied.Spec.Template.Spec.Containers[0].Image)
+	})
+}
+
+func TestTemplateManifests(tOuter *testing.T) {
+	serviceManifest := []byte(`apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: test-service
+spec:
+  template:
+    spec:
+      containers:
+      - image: "{{.TEST_RUN_IMAGE}}"
+`)
+	testutil.Run(tOuter, "manifest is expanded against the environment", func(t *testutil.T) {
+		t.SetEnvs(map[string]string{"TEST_RUN_IMAGE": "gcr.io/test/from-env"})
+		var deployed run.Service
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				http.Error(w, "not found", http.StatusNotFound)
+			default:
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &deployed)
+				w.Write(body)
+			}
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:         "testProject",
+			Region:            "us-central1",
+			TemplateManifests: true,
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{serviceManifest})
+
+		t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestListByConfig))
+		t.CheckDeepEqual("gcr.io/test/from-env", deployed.Spec.Template.Spec.Containers[0].Image)
+	})
+	testutil.Run(tOuter, "undefined template keys fail the deploy", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "not found", http.StatusNotFound)
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:         "testProject",
+			Region:            "us-central1",
+			TemplateManifests: true,
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{[]byte(strings.ReplaceAll(string(serviceManifest), "TEST_RUN_IMAGE", "TEST_RUN_UNDEFINED_KEY"))})
+
+		err = deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestListByConfig)
+		t.CheckErrorContains("unable to expand template in Cloud Run manifest", err)
+	})
+}
+
+func TestBuildArtifactsURL(tOuter *testing.T) {
+	serviceManifest := []byte(`apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: test-service
+spec:
+  template:
+    spec:
+      containers:
+      - image: img
+`)
+	deployWithArtifactsJSON := func(t *testutil.T, artifactsJSON string, localArtifacts []graph.Artifact) (run.Service, error) {
+		artifactServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, artifactsJSON)
+		}))
+		t.Cleanup(artifactServer.Close)
+		var deployed run.Service
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				http.Error(w, "not found", http.StatusNotFound)
+			default:
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &deployed)
+				w.Write(body)
+			}
+		}))
+		t.Cleanup(ts.Close)
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:         "testProject",
+			Region:            "us-central1",
+			BuildArtifactsURL: artifactServer.URL,
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{serviceManifest})
+		return deployed, deployer.Deploy(context.Background(), os.Stderr, localArtifacts, manifestListByConfig)
+	}
+
+	testutil.Run(tOuter, "fetched artifacts are substituted", func(t *testutil.T) {
+		deployed, err := deployWithArtifactsJSON(t, `{"builds":[{"imageName":"img","tag":"gcr.io/test/img:remote"}]}`, nil)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual("gcr.io/test/img:remote", deployed.Spec.Template.Spec.Containers[0].Image)
+	})
+	testutil.Run(tOuter, "locally built artifacts win on collision", func(t *testutil.T) {
+		deployed, err := deployWithArtifactsJSON(t, `{"builds":[{"imageName":"img","tag":"gcr.io/test/img:remote"}]}`,
+			[]graph.Artifact{{ImageName: "img", Tag: "gcr.io/test/img:local"}})
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual("gcr.io/test/img:local", deployed.Spec.Template.Spec.Containers[0].Image)
+	})
+	testutil.Run(tOuter, "invalid JSON fails the deploy", func(t *testutil.T) {
+		_, err := deployWithArtifactsJSON(t, "not json", nil)
+
+		t.CheckErrorContains("are not valid JSON", err)
+	})
+	testutil.Run(tOuter, "builds without a tag are rejected", func(t *testutil.T) {
+		_, err := deployWithArtifactsJSON(t, `{"builds":[{"imageName":"img"}]}`, nil)
+
+		t.CheckErrorContains("every build needs an imageName and a tag", err)
+	})
+}
+
+func TestDeployCreateVsReplace(tOuter *testing.T) {
+	serviceDef := &run.Service{
+		ApiVersion: "serving.knative.dev/v1",
+		Kind:       "Service",
+		Metadata:   &run.ObjectMeta{Name: "test-service"},
+		Spec: &run.ServiceSpec{
+			Template: &run.RevisionTemplate{
+				Spec: &run.RevisionSpec{
+					Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		description    string
+		exists         bool
+		expectedMethod string
+	}{
+		{
+			description:    "missing service is created",
+			exists:         false,
+			expectedMethod: "POST",
+		},
+		{
+			description:    "existing service is replaced",
+			exists:         true,
+			expectedMethod: "PUT",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			var gotMethod string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					if !test.exists {
+						http.Error(w, "not found", http.StatusNotFound)
+						return
+					}
+					b, _ := json.Marshal(serviceDef)
+					w.Write(b)
+				default:
+					if gotMethod == "" {
+						gotMethod = r.Method
+					}
+					body, _ := io.ReadAll(r.Body)
+					w.Write(body)
+				}
+			}))
+			defer ts.Close()
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID: "testProject",
+				Region:    "us-central1",
+			}, configName, WithClientOptions(option.WithEndpoint(ts.URL), option.WithoutAuthentication()))
+			if err != nil {
+				t.Fatalf("unexpected error creating deployer: %v", err)
+			}
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(serviceDef)
+			manifestListByConfig := manifest.NewManifestListByConfig()
+			manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+			t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestListByConfig))
+			t.CheckDeepEqual(test.expectedMethod, gotMethod)
+		})
+	}
+}
+
+func TestClientReuse(tOuter *testing.T) {
+	testutil.Run(tOuter, "deploy and cleanup share one client", func(t *testutil.T) {
+		var constructions int
+		t.Override(&newRunClient, func(ctx context.Context, opts ...option.ClientOption) (*run.APIService, error) {
+			constructions++
+			return run.NewService(ctx, opts...)
+		})
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				http.Error(w, "not found", http.StatusNotFound)
+			case "DELETE":
+				b, _ := json.Marshal(&run.Status{})
+				w.Write(b)
+			default:
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			}
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "testProject",
+			Region:    "us-central1",
+		}, configName)
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+					},
+				},
+			},
+		})
+		manifestListByConfig := manifest.NewManifestListByConfig()
+		manifestListByConfig.Add(configName, manifest.ManifestList{m})
+
+		t.CheckNoError(deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestListByConfig))
+		t.CheckNoError(deployer.Cleanup(context.Background(), os.Stderr, false, manifestListByConfig))
+
+		t.CheckDeepEqual(1, constructions)
+	})
+}
+
+func TestValidateImagePlatforms(tOuter *testing.T) {
+	tests := []struct {
+		description string
+		platforms   []spec.Platform
+		platformErr error
+		shouldErr   bool
+	}{
+		{
+			description: "amd64 image deploys",
+			platforms:   []spec.Platform{{OS: "linux", Architecture: "amd64"}},
+		},
+		{
+			description: "arm64 image targeting managed Cloud Run is rejected",
+			platforms:   []spec.Platform{{OS: "linux", Architecture: "arm64"}},
+			shouldErr:   true,
+		},
+		{
+			description: "multi-platform image containing amd64 deploys",
+			platforms:   []spec.Platform{{OS: "linux", Architecture: "arm64"}, {OS: "linux", Architecture: "amd64"}},
+		},
+		{
+			description: "unknown platform is let through",
+			platformErr: errors.New("registry unreachable"),
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			t.Override(&getImagePlatforms, func(string) ([]spec.Platform, error) {
+				return test.platforms, test.platformErr
+			})
+
+			err := validateImagePlatforms([]graph.Artifact{{ImageName: "test-image", Tag: "gcr.io/test/test-image:tag"}})
+
+			if test.shouldErr {
+				t.CheckErrorContains("managed Cloud Run only runs linux/amd64 images", err)
+			} else {
+				t.CheckNoError(err)
+			}
+		})
+	}
+}
+
+func TestValidateImageRegionColocation(tOuter *testing.T) {
+	tests := []struct {
+		description string
+		tag         string
+		region      string
+		shouldWarn  bool
+	}{
+		{
+			description: "image in the deploy region",
+			tag:         "us-central1-docker.pkg.dev/test/repo/img:tag",
+			region:      "us-central1",
+		},
+		{
+			description: "multi-region host covering the deploy region",
+			tag:         "gcr.io/test/img:tag",
+			region:      "us-central1",
+		},
+		{
+			description: "multi-region host on another continent warns",
+			tag:         "us-docker.pkg.dev/test/repo/img:tag",
+			region:      "asia-east1",
+			shouldWarn:  true,
+		},
+		{
+			description: "region mismatch warns",
+			tag:         "europe-west1-docker.pkg.dev/test/repo/img:tag",
+			region:      "us-central1",
+			shouldWarn:  true,
+		},
+		{
+			description: "eu multi-region host covering a european region",
+			tag:         "eu.gcr.io/test/img:tag",
+			region:      "europe-west1",
+		},
+		{
+			description: "host without a location is let through",
+			tag:         "docker.io/library/img:tag",
+			region:      "us-central1",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(tOuter, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			validateImageRegionColocation([]graph.Artifact{{ImageName: "img", Tag: test.tag}}, test.region)
+
+			if test.shouldWarn {
+				t.CheckDeepEqual(1, len(fakeWarner.Warnings))
+				t.CheckContains("co-locating them avoids cross-region latency", fakeWarner.Warnings[0])
+			} else {
+				t.CheckDeepEqual(0, len(fakeWarner.Warnings))
+			}
+		})
+	}
+}
+
+func TestUploadRepoValidation(tOuter *testing.T) {
+	testutil.Run(tOuter, "uploadRepo outside a supported registry is rejected", func(t *testutil.T) {
+		t.Override(&headCommitMessage, func() string { return "" })
+		_, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:  "test-project",
+			Region:     "us-central1",
+			UploadRepo: "docker.io/test/repo",
+		}, configName)
+		t.CheckErrorContains("uploadRepo", err)
+	})
+}
+
+func TestDeployImageDiffReport(tOuter *testing.T) {
+	testutil.Run(tOuter, "diff names the old and new image for each container", func(t *testutil.T) {
+		current := &run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service", Namespace: "test-project"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{
+							{Name: "app", Image: "gcr.io/test/app:old"},
+							{Name: "sidecar", Image: "gcr.io/test/sidecar:old"},
+						},
+					},
+				},
+			},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				b, err := json.Marshal(current)
+				if err != nil {
+					http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Write(b)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(body)
+		}))
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		deployer.reportImageDiff = true
+		serviceManifest, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{
+							{Name: "app", Image: "gcr.io/test/app"},
+							{Name: "sidecar", Image: "gcr.io/test/sidecar"},
+						},
+					},
+				},
+			},
+		})
+		artifacts := []graph.Artifact{
+			{ImageName: "gcr.io/test/app", Tag: "gcr.io/test/app:new"},
+			{ImageName: "gcr.io/test/sidecar", Tag: "gcr.io/test/sidecar:new"},
+		}
+		var out bytes.Buffer
+		if err := deployer.deployToCloudRun(context.Background(), &out, serviceManifest, artifacts); err != nil {
+			t.Fatalf("Expected success but got err: %v", err)
+		}
+		t.CheckContains("app: gcr.io/test/app:old -> gcr.io/test/app:new", out.String())
+		t.CheckContains("sidecar: gcr.io/test/sidecar:old -> gcr.io/test/sidecar:new", out.String())
+	})
+}
+
+func TestDependencies(tOuter *testing.T) {
+	testutil.Run(tOuter, "env and credential files are watched, absolute paths skipped", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("app.env", "A=1\n").
+			Write("creds.json", "{}")
+		tmpDir.Chdir()
+		t.Override(&headCommitMessage, func() string { return "" })
+
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:       "test-project",
+			Region:          "us-central1",
+			EnvFile:         "app.env",
+			CredentialsFile: "creds.json",
+		}, configName)
+		t.CheckNoError(err)
+		deps, err := deployer.Dependencies()
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"app.env", "creds.json"}, deps)
+
+		// credential files outside the workspace are not watched.
+		deployer, err = NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID:       "test-project",
+			Region:          "us-central1",
+			EnvFile:         "app.env",
+			CredentialsFile: tmpDir.Path("creds.json"),
+		}, configName)
+		t.CheckNoError(err)
+		deps, err = deployer.Dependencies()
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{"app.env"}, deps)
+	})
+}
+
+func TestDeployReportsServiceURL(tOuter *testing.T) {
+	testutil.Run(tOuter, "service URL is printed after deploy", func(t *testutil.T) {
+		deployed := false
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				if !deployed {
+					http.Error(w, "not found", http.StatusNotFound)
+					return
+				}
+				b, _ := json.Marshal(&run.Service{
+					ApiVersion: "serving.knative.dev/v1",
+					Kind:       "Service",
+					Metadata:   &run.ObjectMeta{Name: "test-service", Namespace: "test-project"},
+					Status:     &run.ServiceStatus{Url: "https://test-service-abc123.a.run.app"},
+				})
+				w.Write(b)
+				return
+			}
+			deployed = true
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(body)
+		}))
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		serviceManifest, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec: &run.ServiceSpec{
+				Template: &run.RevisionTemplate{
+					Spec: &run.RevisionSpec{
+						Containers: []*run.Container{{Image: "gcr.io/test/image"}},
+					},
+				},
+			},
+		})
+		var out bytes.Buffer
+		if err := deployer.deployToCloudRun(context.Background(), &out, serviceManifest, nil); err != nil {
+			t.Fatalf("Expected success but got err: %v", err)
+		}
+		t.CheckContains("Cloud Run service test-service is available at https://test-service-abc123.a.run.app", out.String())
+	})
+}
+
+func TestDeployMultipleServices(tOuter *testing.T) {
+	testutil.Run(tOuter, "two services deploy from a single config", func(t *testutil.T) {
+		var mu sync.Mutex
+		deployed := map[string]string{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				http.Error(w, "want to return empty default", http.StatusNotFound)
+				return
+			}
+			var service run.Service
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Unable to read body: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if err = json.Unmarshal(body, &service); err != nil {
+				http.Error(w, "Unable to parse service: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			deployed[service.Metadata.Name] = service.Spec.Template.Spec.Containers[0].Image
+			mu.Unlock()
+			b, err := json.Marshal(service)
+			if err != nil {
+				http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(b)
+		}))
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		serviceFor := func(name, image string) []byte {
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: name},
+				Spec: &run.ServiceSpec{
+					Template: &run.RevisionTemplate{
+						Spec: &run.RevisionSpec{
+							Containers: []*run.Container{{Image: image}},
+						},
+					},
+				},
+			})
+			return m
+		}
+		artifacts := []graph.Artifact{
+			{ImageName: "gcr.io/test/frontend", Tag: "gcr.io/test/frontend:tag1"},
+			{ImageName: "gcr.io/test/backend", Tag: "gcr.io/test/backend:tag2"},
+		}
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{
+			serviceFor("frontend", "gcr.io/test/frontend"),
+			serviceFor("backend", "gcr.io/test/backend"),
+		})
+
+		if err := deployer.Deploy(context.Background(), os.Stderr, artifacts, manifestsByConfig); err != nil {
+			t.Fatalf("Expected success but got err: %v", err)
+		}
+
+		t.CheckDeepEqual(map[string]string{
+			"frontend": "gcr.io/test/frontend:tag1",
+			"backend":  "gcr.io/test/backend:tag2",
+		}, deployed)
+		t.CheckDeepEqual(2, len(deployer.getMonitor().Resources))
+	})
+	testutil.Run(tOuter, "cleanup deletes every service of the config", func(t *testutil.T) {
+		var mu sync.Mutex
+		var deleted []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "DELETE" {
+				http.Error(w, "unexpected method: "+r.Method, http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+			b, _ := json.Marshal(&run.Status{})
+			w.Write(b)
+		}))
+		defer ts.Close()
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "test-project",
+			Region:    "us-central1",
+		}, configName, WithClientOptions(option.WithEndpoint(ts.URL), option.WithoutAuthentication()))
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.useGcpOptions = false
+		serviceFor := func(name string) []byte {
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: name},
+			})
+			return m
+		}
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{
+			serviceFor("frontend"),
+			serviceFor("backend"),
+		})
+
+		t.CheckNoError(deployer.Cleanup(context.Background(), os.Stderr, false, manifestsByConfig))
+
+		t.CheckDeepEqual([]string{
+			"/v1/projects/test-project/locations/us-central1/services/frontend",
+			"/v1/projects/test-project/locations/us-central1/services/backend",
+		}, deleted)
+	})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudrun
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/constants"
+	eventV2 "github.com/GoogleContainerTools/skaffold/pkg/skaffold/event/v2"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/output"
+)
+
+var (
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = 2 * time.Second
+	defaultRetryMaxBackoff = 16 * time.Second
+
+	// emitRetryEvent surfaces a retry as a structured event, overridable for testing.
+	emitRetryEvent = func(attempt int, reason string) {
+		fmt.Fprintf(eventV2.NewLogger(constants.Deploy, "cloudrun"),
+			"Retrying Cloud Run request: attempt %d: %s\n", attempt, reason)
+	}
+)
+
+// retryReason classifies an error returned by the Cloud Run API into a human-readable
+// retry reason. It returns false when the error is not considered transient.
+func retryReason(err error) (string, bool) {
+	gErr, ok := err.(*googleapi.Error)
+	if !ok {
+		return "", false
+	}
+	switch gErr.Code {
+	case http.StatusServiceUnavailable:
+		return "service unavailable", true
+	case http.StatusTooManyRequests:
+		return "rate limited", true
+	case http.StatusConflict:
+		return "conflict", true
+	}
+	return "", false
+}
+
+// withRetries runs the given Cloud Run API call, retrying transient errors (503/429/409)
+// with exponential backoff, doubling the configured base delay after every attempt up to
+// the configured cap. Each retry emits an event carrying the attempt number and the
+// classified reason so the backoff behavior shows up in CI logs.
+func (d *Deployer) withRetries(out io.Writer, op func() error) error {
+	delay := d.retryBaseDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = op()
+		if err == nil {
+			return nil
+		}
+		reason, retryable := retryReason(err)
+		if !retryable || attempt >= d.retryAttempts {
+			return err
+		}
+		emitRetryEvent(attempt, reason)
+		output.Yellow.Fprintf(out, "Retrying Cloud Run request (attempt %d of %d): %s\n", attempt+1, d.retryAttempts, reason)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > d.retryMaxDelay {
+			delay = d.retryMaxDelay
+		}
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/run/v1"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/label"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/graph"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes/manifest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/runcontext"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDeployRetryEvents(t *testing.T) {
+	tests := []struct {
+		description    string
+		failures       int
+		httpErr        int
+		expectedEvents []string
+		shouldErr      bool
+	}{
+		{
+			description:    "retry events emitted for transient 503s",
+			failures:       2,
+			httpErr:        http.StatusServiceUnavailable,
+			expectedEvents: []string{"attempt 1: service unavailable", "attempt 2: service unavailable"},
+		},
+		{
+			description:    "rate limiting is classified and gives up after max attempts",
+			failures:       5,
+			httpErr:        http.StatusTooManyRequests,
+			expectedEvents: []string{"attempt 1: rate limited", "attempt 2: rate limited"},
+			shouldErr:      true,
+		},
+		{
+			description: "non-retryable errors fail immediately",
+			failures:    1,
+			httpErr:     http.StatusBadRequest,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&defaultRetryBackoff, time.Millisecond)
+			var events []string
+			t.Override(&emitRetryEvent, func(attempt int, reason string) {
+				events = append(events, fmt.Sprintf("attempt %d: %s", attempt, reason))
+			})
+
+			var creates int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					http.Error(w, "not found", http.StatusNotFound)
+					return
+				}
+				creates++
+				if creates <= test.failures {
+					http.Error(w, "test expecting error", test.httpErr)
+					return
+				}
+				b, _ := json.Marshal(&run.Service{})
+				w.Write(b)
+			}))
+			defer ts.Close()
+
+			t.Override(&headCommitMessage, func() string { return "" })
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{ProjectID: "testProject", Region: "us-central1"}, configName)
+			deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			deployer.useGcpOptions = false
+			m, _ := json.Marshal(&run.Service{
+				ApiVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Metadata:   &run.ObjectMeta{Name: "test-service"},
+				Spec:       minimalServiceSpec(),
+			})
+			manifestsByConfig := manifest.NewManifestListByConfig()
+			manifestsByConfig.Add(configName, manifest.ManifestList{m})
+
+			err := deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestsByConfig)
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expectedEvents, events)
+		})
+	}
+}
+
+func TestRetryConfig(t *testing.T) {
+	testutil.Run(t, "configured attempts bound the retry loop", func(t *testutil.T) {
+		var creates int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			creates++
+			http.Error(w, "test expecting error", http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+		t.Override(&emitRetryEvent, func(int, string) {})
+		t.Override(&headCommitMessage, func() string { return "" })
+		deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+			ProjectID: "testProject",
+			Region:    "us-central1",
+			RetryConfig: &latest.CloudRunRetryConfig{
+				BaseDelay:   "1ms",
+				MaxDelay:    "2ms",
+				MaxAttempts: 5,
+			},
+		}, configName)
+		if err != nil {
+			t.Fatalf("unexpected error creating deployer: %v", err)
+		}
+		deployer.clientOptions = append(deployer.clientOptions, option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+		deployer.useGcpOptions = false
+		m, _ := json.Marshal(&run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+			Metadata:   &run.ObjectMeta{Name: "test-service"},
+			Spec:       minimalServiceSpec(),
+		})
+		manifestsByConfig := manifest.NewManifestListByConfig()
+		manifestsByConfig.Add(configName, manifest.ManifestList{m})
+
+		err = deployer.Deploy(context.Background(), os.Stderr, []graph.Artifact{}, manifestsByConfig)
+
+		t.CheckError(true, err)
+		t.CheckDeepEqual(5, creates)
+	})
+}
+
+func TestRetryConfigValidation(t *testing.T) {
+	tests := []struct {
+		description string
+		config      *latest.CloudRunRetryConfig
+		errContains string
+	}{
+		{
+			description: "negative attempts are rejected",
+			config:      &latest.CloudRunRetryConfig{MaxAttempts: -1},
+			errContains: "retryConfig.maxAttempts",
+		},
+		{
+			description: "non-duration base delay is rejected",
+			config:      &latest.CloudRunRetryConfig{BaseDelay: "soon"},
+			errContains: "retryConfig.baseDelay",
+		},
+		{
+			description: "zero max delay is rejected",
+			config:      &latest.CloudRunRetryConfig{MaxDelay: "0s"},
+			errContains: "must be positive",
+		},
+		{
+			description: "max delay below base delay is rejected",
+			config:      &latest.CloudRunRetryConfig{BaseDelay: "10s", MaxDelay: "5s"},
+			errContains: "must not be less than baseDelay",
+		},
+		{
+			description: "base delay above the default cap raises the cap",
+			config:      &latest.CloudRunRetryConfig{BaseDelay: "30s"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.Override(&headCommitMessage, func() string { return "" })
+
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:   "testProject",
+				Region:      "us-central1",
+				RetryConfig: test.config,
+			}, configName)
+
+			if test.errContains != "" {
+				t.CheckErrorContains(test.errContains, err)
+				return
+			}
+			t.CheckNoError(err)
+			t.CheckDeepEqual(deployer.retryBaseDelay, deployer.retryMaxDelay)
+		})
+	}
+}
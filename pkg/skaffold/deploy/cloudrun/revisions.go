@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudrun
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/api/run/v1"
+
+	sErrors "github.com/GoogleContainerTools/skaffold/pkg/skaffold/errors"
+	"github.com/GoogleContainerTools/skaffold/proto/v1"
+)
+
+// pruneRevisions deletes the oldest revisions of a Service beyond the configured
+// maxRevisions limit to control revision sprawl. Revisions that still receive
+// traffic, and the latest created or ready revision, are never deleted.
+func (d *Deployer) pruneRevisions(crclient *run.APIService, svc *RunResourceName) error {
+	if d.maxRevisions <= 0 {
+		return nil
+	}
+	parent := fmt.Sprintf("projects/%s/locations/%s", svc.Project, svc.Region)
+	listCall := crclient.Projects.Locations.Revisions.List(parent).LabelSelector(fmt.Sprintf("serving.knative.dev/service=%s", svc.Service))
+	revList, err := listCall.Do()
+	if err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to list Cloud Run revisions: %w", err), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_GET_SERVICE_ERR,
+		})
+	}
+	if len(revList.Items) <= d.maxRevisions {
+		return nil
+	}
+
+	service, err := crclient.Projects.Locations.Services.Get(svc.String()).Do()
+	if err != nil {
+		return sErrors.NewError(fmt.Errorf("unable to check Cloud Run traffic assignments: %w", err), &proto.ActionableErr{
+			Message: err.Error(),
+			ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_GET_SERVICE_ERR,
+		})
+	}
+	serving := map[string]bool{
+		service.Status.LatestCreatedRevisionName: true,
+		service.Status.LatestReadyRevisionName:   true,
+	}
+	for _, target := range service.Status.Traffic {
+		serving[target.RevisionName] = true
+	}
+
+	revisions := revList.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Metadata.CreationTimestamp < revisions[j].Metadata.CreationTimestamp
+	})
+	excess := len(revisions) - d.maxRevisions
+	for _, revision := range revisions {
+		if excess <= 0 {
+			break
+		}
+		if serving[revision.Metadata.Name] {
+			continue
+		}
+		deleteCall := crclient.Projects.Locations.Revisions.Delete(fmt.Sprintf("%s/revisions/%s", parent, revision.Metadata.Name))
+		if _, err := deleteCall.Do(); err != nil {
+			return sErrors.NewError(fmt.Errorf("unable to delete Cloud Run revision %q: %w", revision.Metadata.Name, err), &proto.ActionableErr{
+				Message: err.Error(),
+				ErrCode: proto.StatusCode_DEPLOY_CLOUD_RUN_DELETE_SERVICE_ERR,
+			})
+		}
+		excess--
+	}
+	return nil
+}
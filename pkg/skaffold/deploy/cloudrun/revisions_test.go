@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudrun
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/run/v1"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/label"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/runcontext"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestPruneRevisions(t *testing.T) {
+	revisions := []*run.Revision{
+		{Metadata: &run.ObjectMeta{Name: "test-service-00001", CreationTimestamp: "2022-01-01T00:00:00Z"}},
+		{Metadata: &run.ObjectMeta{Name: "test-service-00002", CreationTimestamp: "2022-01-02T00:00:00Z"}},
+		{Metadata: &run.ObjectMeta{Name: "test-service-00003", CreationTimestamp: "2022-01-03T00:00:00Z"}},
+		{Metadata: &run.ObjectMeta{Name: "test-service-00004", CreationTimestamp: "2022-01-04T00:00:00Z"}},
+		{Metadata: &run.ObjectMeta{Name: "test-service-00005", CreationTimestamp: "2022-01-05T00:00:00Z"}},
+	}
+	tests := []struct {
+		description     string
+		maxRevisions    int
+		traffic         []*run.TrafficTarget
+		expectedDeletes []string
+	}{
+		{
+			description:  "five revisions with a limit of 3 deletes the two oldest",
+			maxRevisions: 3,
+			traffic:      []*run.TrafficTarget{{RevisionName: "test-service-00005", Percent: 100}},
+			expectedDeletes: []string{
+				"test-service-00001",
+				"test-service-00002",
+			},
+		},
+		{
+			description:  "revisions still receiving traffic are kept",
+			maxRevisions: 3,
+			traffic:      []*run.TrafficTarget{{RevisionName: "test-service-00001", Percent: 100}},
+			expectedDeletes: []string{
+				"test-service-00002",
+				"test-service-00003",
+			},
+		},
+		{
+			description:  "no pruning when the limit is not exceeded",
+			maxRevisions: 5,
+			traffic:      []*run.TrafficTarget{{RevisionName: "test-service-00005", Percent: 100}},
+		},
+		{
+			description:  "no pruning when maxRevisions is unset",
+			maxRevisions: 0,
+			traffic:      []*run.TrafficTarget{{RevisionName: "test-service-00005", Percent: 100}},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var deleted []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "DELETE":
+					parts := strings.Split(r.URL.Path, "/")
+					deleted = append(deleted, parts[len(parts)-1])
+					b, _ := json.Marshal(&run.Status{})
+					w.Write(b)
+				case strings.HasSuffix(r.URL.Path, "/revisions"):
+					b, _ := json.Marshal(&run.ListRevisionsResponse{Items: revisions})
+					w.Write(b)
+				default:
+					b, _ := json.Marshal(&run.Service{
+						Metadata: &run.ObjectMeta{Name: "test-service"},
+						Status: &run.ServiceStatus{
+							LatestCreatedRevisionName: "test-service-00005",
+							LatestReadyRevisionName:   "test-service-00005",
+							Traffic:                   test.traffic,
+						},
+					})
+					w.Write(b)
+				}
+			}))
+			defer ts.Close()
+
+			deployer, _ := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:    "testProject",
+				Region:       "us-central1",
+				MaxRevisions: test.maxRevisions,
+			}, configName)
+			crclient, err := run.NewService(context.Background(), option.WithEndpoint(ts.URL), option.WithoutAuthentication())
+			t.CheckNoError(err)
+
+			err = deployer.pruneRevisions(crclient, &RunResourceName{
+				Project: "testProject",
+				Region:  "us-central1",
+				Service: "test-service",
+			})
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedDeletes, deleted)
+		})
+	}
+}
@@ -51,6 +51,9 @@ type Monitor struct {
 	statusCheckDeadline time.Duration
 	pollPeriod          time.Duration
 	reportStatusTime    time.Duration
+
+	// readyConditionType is the status condition Services are polled on.
+	readyConditionType string
 }
 
 func NewMonitor(labeller *label.DefaultLabeller, clientOptions []option.ClientOption) *Monitor {
@@ -60,6 +63,7 @@ func NewMonitor(labeller *label.DefaultLabeller, clientOptions []option.ClientOp
 		statusCheckDeadline: defaultStatusCheckDeadline,
 		pollPeriod:          defaultPollPeriod,
 		reportStatusTime:    defaultReportStatusTime,
+		readyConditionType:  "Ready",
 	}
 }
 
@@ -79,7 +83,7 @@ func (s *Monitor) check(ctx context.Context, out io.Writer) error {
 		var sub runSubresource
 		switch resource.Type() {
 		case typeService:
-			sub = &runServiceResource{path: resource.String()}
+			sub = &runServiceResource{path: resource.String(), conditionType: s.readyConditionType}
 		case typeJob:
 			sub = &runJobResource{path: resource.String()}
 		default:
@@ -248,10 +252,7 @@ func (r *runResource) checkStatus(crClient *run.APIService) {
 
 	case "False":
 		r.completed = true
-		r.updateStatus(&proto.ActionableErr{
-			ErrCode: proto.StatusCode_STATUSCHECK_UNHEALTHY,
-			Message: fmt.Sprintf("%s failed to start: %v", r.resource.Type(), ready.Message),
-		})
+		r.updateStatus(r.failedStatus(ready))
 	default:
 		// status is unknown
 		r.updateStatus(&proto.ActionableErr{
@@ -261,6 +262,28 @@ func (r *runResource) checkStatus(crClient *run.APIService) {
 	}
 }
 
+// failedStatus maps the reason of a failed Ready condition to an actionable
+// error. Cloud Run reports container startup problems with dedicated reasons
+// that deserve more specific guidance than a generic failure message.
+func (r *runResource) failedStatus(ready *run.GoogleCloudRunV1Condition) *proto.ActionableErr {
+	switch ready.Reason {
+	case "RevisionFailed":
+		return &proto.ActionableErr{
+			ErrCode: proto.StatusCode_STATUSCHECK_UNHEALTHY,
+			Message: fmt.Sprintf("%s failed to start: the latest revision failed to deploy: %v. Check the Cloud Run logs for the revision to diagnose", r.resource.Type(), ready.Message),
+		}
+	case "ContainerHealthcheckFailed":
+		return &proto.ActionableErr{
+			ErrCode: proto.StatusCode_STATUSCHECK_RUN_CONTAINER_ERR,
+			Message: fmt.Sprintf("%s failed to start: a container failed its healthcheck: %v. Verify the container listens on the expected port and passes its startup probe", r.resource.Type(), ready.Message),
+		}
+	}
+	return &proto.ActionableErr{
+		ErrCode: proto.StatusCode_STATUSCHECK_UNHEALTHY,
+		Message: fmt.Sprintf("%s failed to start: %v", r.resource.Type(), ready.Message),
+	}
+}
+
 // printResourceStatus prints resource statuses until all status check are completed or context is cancelled.
 func (s *Monitor) printResourceStatus(ctx context.Context, out io.Writer, resources []*runResource) {
 	ticker := time.NewTicker(s.reportStatusTime)
@@ -312,6 +335,8 @@ func (s *Monitor) printStatusCheckSummary(out io.Writer, c *counter, r *runResou
 
 type runServiceResource struct {
 	path string
+	// conditionType is the status condition polled on; empty means Ready.
+	conditionType string
 
 	url            string
 	latestRevision string
@@ -326,6 +351,10 @@ func (r *runServiceResource) getTerminalStatus(crClient *run.APIService) (*run.G
 			Message: fmt.Sprintf("Unable to check Cloud Run status: %v", err),
 		}
 	}
+	conditionType := r.conditionType
+	if conditionType == "" {
+		conditionType = "Ready"
+	}
 	// find the ready condition
 	var ready *run.GoogleCloudRunV1Condition
 
@@ -333,7 +362,7 @@ func (r *runServiceResource) getTerminalStatus(crClient *run.APIService) (*run.G
 	// same as no status being set.
 	if res.Status.ObservedGeneration == res.Metadata.Generation {
 		for _, cond := range res.Status.Conditions {
-			if cond.Type == "Ready" {
+			if cond.Type == conditionType {
 				ready = cond
 				break
 			}
@@ -30,6 +30,7 @@ import (
 	"google.golang.org/protobuf/testing/protocmp"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/label"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner/runcontext"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	proto "github.com/GoogleContainerTools/skaffold/proto/v2"
 	"github.com/GoogleContainerTools/skaffold/testutil"
@@ -219,6 +220,87 @@ func TestPollServiceStatus(t *testing.T) {
 			},
 			expected: &proto.ActionableErr{Message: "Service started", ErrCode: proto.StatusCode_STATUSCHECK_SUCCESS},
 		},
+		{
+			description: "test revision failed surfaces actionable error",
+			resource:    RunResourceName{Project: "tp", Region: "tr", Service: "test-service"},
+			responses: []run.Service{
+				{
+					ApiVersion: "serving.knative.dev/v1",
+					Metadata: &run.ObjectMeta{
+						Generation: 1,
+					},
+					Status: &run.ServiceStatus{
+						ObservedGeneration: 1,
+						Conditions: []*run.GoogleCloudRunV1Condition{
+							{
+								Type:    "Ready",
+								Status:  "False",
+								Reason:  "RevisionFailed",
+								Message: "Revision 'test-service-00001' is not ready",
+							},
+						},
+					},
+				},
+			},
+			expected: &proto.ActionableErr{
+				Message: "Service failed to start: the latest revision failed to deploy: Revision 'test-service-00001' is not ready. Check the Cloud Run logs for the revision to diagnose",
+				ErrCode: proto.StatusCode_STATUSCHECK_UNHEALTHY,
+			},
+		},
+		{
+			description: "test container healthcheck failure surfaces actionable error",
+			resource:    RunResourceName{Project: "tp", Region: "tr", Service: "test-service"},
+			responses: []run.Service{
+				{
+					ApiVersion: "serving.knative.dev/v1",
+					Metadata: &run.ObjectMeta{
+						Generation: 1,
+					},
+					Status: &run.ServiceStatus{
+						ObservedGeneration: 1,
+						Conditions: []*run.GoogleCloudRunV1Condition{
+							{
+								Type:    "Ready",
+								Status:  "False",
+								Reason:  "ContainerHealthcheckFailed",
+								Message: "The user-provided container failed the configured startup probe checks",
+							},
+						},
+					},
+				},
+			},
+			expected: &proto.ActionableErr{
+				Message: "Service failed to start: a container failed its healthcheck: The user-provided container failed the configured startup probe checks. Verify the container listens on the expected port and passes its startup probe",
+				ErrCode: proto.StatusCode_STATUSCHECK_RUN_CONTAINER_ERR,
+			},
+		},
+		{
+			description: "test failure without a known reason reports generic message",
+			resource:    RunResourceName{Project: "tp", Region: "tr", Service: "test-service"},
+			responses: []run.Service{
+				{
+					ApiVersion: "serving.knative.dev/v1",
+					Metadata: &run.ObjectMeta{
+						Generation: 1,
+					},
+					Status: &run.ServiceStatus{
+						ObservedGeneration: 1,
+						Conditions: []*run.GoogleCloudRunV1Condition{
+							{
+								Type:    "Ready",
+								Status:  "False",
+								Reason:  "RouteMissing",
+								Message: "Route is missing",
+							},
+						},
+					},
+				},
+			},
+			expected: &proto.ActionableErr{
+				Message: "Service failed to start: Route is missing",
+				ErrCode: proto.StatusCode_STATUSCHECK_UNHEALTHY,
+			},
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -247,6 +329,50 @@ func TestPollServiceStatus(t *testing.T) {
 	}
 }
 
+func TestPollServiceCustomCondition(t *testing.T) {
+	testutil.Run(t, "monitor waits on RoutesReady instead of Ready", func(t *testutil.T) {
+		resName := RunResourceName{Project: "tp", Region: "tr", Service: "test-service"}
+		response := run.Service{
+			ApiVersion: "serving.knative.dev/v1",
+			Metadata: &run.ObjectMeta{
+				Generation: 1,
+			},
+			Status: &run.ServiceStatus{
+				ObservedGeneration: 1,
+				Conditions: []*run.GoogleCloudRunV1Condition{
+					{
+						Type:    "Ready",
+						Status:  "False",
+						Message: "Revision failed",
+					},
+					{
+						Type:    "RoutesReady",
+						Status:  "True",
+						Message: "Routes Ready",
+					},
+				},
+			},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := json.Marshal(response)
+			if err != nil {
+				http.Error(w, "unable to marshal response: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Write(b)
+		}))
+		defer ts.Close()
+		testEvent.InitializeState([]latest.Pipeline{{}})
+
+		resource := &runResource{resource: resName, sub: &runServiceResource{path: resName.String(), conditionType: "RoutesReady"}}
+		resource.pollResourceStatus(context.Background(), 5*time.Second, 1*time.Second, []option.ClientOption{option.WithEndpoint(ts.URL), option.WithoutAuthentication()}, false)
+		t.CheckDeepEqual(&proto.ActionableErr{
+			Message: "Service started",
+			ErrCode: proto.StatusCode_STATUSCHECK_SUCCESS,
+		}, resource.status.ae, protocmp.Transform())
+	})
+}
+
 func TestPollJobStatus(t *testing.T) {
 	tests := []struct {
 		description string
@@ -438,3 +564,46 @@ func TestMonitorPrintStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestReadinessTimeout(t *testing.T) {
+	tests := []struct {
+		description      string
+		readinessTimeout string
+		expectedDeadline time.Duration
+		shouldErr        bool
+	}{
+		{
+			description:      "no readiness timeout keeps the default deadline",
+			expectedDeadline: defaultStatusCheckDeadline,
+		},
+		{
+			description:      "readiness timeout shorter than the default deadline wins",
+			readinessTimeout: "2m",
+			expectedDeadline: 2 * time.Minute,
+		},
+		{
+			description:      "readiness timeout longer than the default deadline is ignored",
+			readinessTimeout: "20m",
+			expectedDeadline: defaultStatusCheckDeadline,
+		},
+		{
+			description:      "readiness timeout that does not parse is rejected",
+			readinessTimeout: "2 minutes",
+			shouldErr:        true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			deployer, err := NewDeployer(&runcontext.RunContext{}, &label.DefaultLabeller{}, &latest.CloudRunDeploy{
+				ProjectID:        "testProject",
+				Region:           "us-central1",
+				ReadinessTimeout: test.readinessTimeout,
+			}, configName)
+
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				t.CheckDeepEqual(test.expectedDeadline, deployer.getMonitor().statusCheckDeadline)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/instrumentation"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/output/log"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 )
 
 const imageField = "image"
@@ -133,6 +134,31 @@ func (is *imageSaver) Visit(gk apimachinery.GroupKind, navpath string, o map[str
 	return false
 }
 
+// CheckUnbuiltImages warns for every image referenced by the manifests that is neither
+// produced by the build nor pinned to an external reference (a tag or digest). Such
+// references usually indicate a forgotten artifact entry in the build section.
+func (l *ManifestList) CheckUnbuiltImages(builds []graph.Artifact, rs ResourceSelector) error {
+	images, err := l.GetImages(rs)
+	if err != nil {
+		return err
+	}
+	built := make(map[string]bool)
+	for _, b := range builds {
+		built[docker.SanitizeImageName(b.ImageName)] = true
+	}
+	for _, image := range images {
+		if built[image.ImageName] {
+			continue
+		}
+		parsed, err := docker.ParseReference(image.Tag)
+		if err != nil || parsed.FullyQualified {
+			continue
+		}
+		warnings.Printf("image [%s] is not built by this configuration and is not pinned to a tag or digest: it may be a forgotten artifact", image.Tag)
+	}
+	return nil
+}
+
 // ReplaceImages replaces image names in a list of manifests.
 // It doesn't replace images that are referenced by digest.
 func (l *ManifestList) ReplaceImages(ctx context.Context, builds []graph.Artifact, rs ResourceSelector) (ManifestList, error) {
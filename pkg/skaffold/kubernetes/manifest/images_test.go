@@ -63,6 +63,57 @@ spec:
 	testutil.CheckErrorAndDeepEqual(t, false, err, expectedImages, actual)
 }
 
+func TestCheckUnbuiltImages(t *testing.T) {
+	tests := []struct {
+		description      string
+		manifest         string
+		builds           []graph.Artifact
+		expectedWarnings []string
+	}{
+		{
+			description: "unbuilt local-looking image warns",
+			manifest: `
+apiVersion: v1
+kind: Pod
+spec:
+  containers:
+  - image: local-image
+    name: local
+`,
+			builds:           []graph.Artifact{{ImageName: "other-image", Tag: "other-image:tag"}},
+			expectedWarnings: []string{"image [local-image] is not built by this configuration and is not pinned to a tag or digest: it may be a forgotten artifact"},
+		},
+		{
+			description: "built and pinned images don't warn",
+			manifest: `
+apiVersion: v1
+kind: Pod
+spec:
+  containers:
+  - image: built-image
+    name: built
+  - image: gcr.io/external/image:1.2.3
+    name: pinned
+  - image: gcr.io/external/image@sha256:81daf011d63b68cfa514ddab7741a1adddd59d3264118dfb0fd9266328bb8883
+    name: digest
+`,
+			builds: []graph.Artifact{{ImageName: "built-image", Tag: "built-image:tag"}},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			manifests := ManifestList{[]byte(test.manifest)}
+			err := manifests.CheckUnbuiltImages(test.builds, NewResourceSelectorImages(TransformAllowlist, TransformDenylist))
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
 func TestReplaceRemoteManifestImages(t *testing.T) {
 	manifests := ManifestList{[]byte(`
 apiVersion: v1
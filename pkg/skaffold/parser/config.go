@@ -37,6 +37,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/tags"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util/stringslice"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 )
 
 type configOpts struct {
@@ -112,13 +113,16 @@ func GetConfigSet(ctx context.Context, opts config.SkaffoldOptions) (SkaffoldCon
 func getConfigs(ctx context.Context, cfgOpts configOpts, opts config.SkaffoldOptions, r *record) (SkaffoldConfigSet, map[string]configlocations.YAMLOverrideInfo, error) {
 	fieldsOverrodeByProfile := map[string]configlocations.YAMLOverrideInfo{}
 
-	parsed, err := schema.ParseConfigAndUpgrade(cfgOpts.file)
+	parsed, deprecations, err := schema.ParseConfigAndUpgrade(cfgOpts.file)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, nil, sErrors.MainConfigFileNotFoundErr(cfgOpts.file, err)
 		}
 		return nil, nil, sErrors.ConfigParsingError(err)
 	}
+	for _, deprecation := range deprecations {
+		warnings.Printf("%s", deprecation)
+	}
 
 	if !util.IsURL(cfgOpts.file) && !filepath.IsAbs(cfgOpts.file) && cfgOpts.file != "-" {
 		cwd, _ := util.RealWorkDir()
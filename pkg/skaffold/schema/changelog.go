@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta5"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v3alpha1"
+)
+
+// upgradeChanges records the notable field-level changes applied when upgrading
+// *to* the given schema version, mirroring the "Config changes" doc comments on
+// each version's Upgrade method. Versions without an entry upgrade without
+// touching any fields.
+var upgradeChanges = map[string][]string{
+	v2beta5.Version: {
+		"build.cluster.pullSecret renamed to build.cluster.pullSecretPath",
+		"build.artifacts.buildpack renamed to build.artifacts.buildpacks",
+	},
+	v3alpha1.Version: {
+		"deploy.kubectl.manifests moved to manifests.rawYaml",
+		"deploy.kustomize.paths moved to manifests.kustomize.paths",
+		"deploy.kustomize.buildArgs moved to manifests.kustomize.buildArgs",
+		"deploy.helm releases moved to manifests.helm",
+	},
+}
+
+// UpgradeChangelog lists the field-level changes applied along the upgrade path
+// from one schema version to another, one entry per change, prefixed with the
+// version that introduced it. It powers the `skaffold fix` user output.
+func UpgradeChangelog(from string, to string) ([]string, error) {
+	fromIndex, err := versionIndex(from)
+	if err != nil {
+		return nil, err
+	}
+	toIndex, err := versionIndex(to)
+	if err != nil {
+		return nil, err
+	}
+	if fromIndex > toIndex {
+		return nil, fmt.Errorf("unable to describe a downgrade from %q to %q", from, to)
+	}
+
+	var changelog []string
+	for _, version := range AllVersions[fromIndex+1 : toIndex+1] {
+		for _, change := range upgradeChanges[version.APIVersion] {
+			changelog = append(changelog, fmt.Sprintf("%s: %s", version.APIVersion, change))
+		}
+	}
+	return changelog, nil
+}
+
+// versionIndex finds the position of apiVersion in the upgrade chain.
+func versionIndex(apiVersion string) (int, error) {
+	for i, version := range AllVersions {
+		if version.APIVersion == apiVersion {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown apiVersion %q", apiVersion)
+}
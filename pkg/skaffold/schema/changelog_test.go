@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	latestV1 "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta29"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta4"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta6"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestUpgradeChangelog(t *testing.T) {
+	tests := []struct {
+		description string
+		from        string
+		to          string
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "upgrade spanning the pullSecret rename",
+			from:        v2beta4.Version,
+			to:          v2beta6.Version,
+			expected: []string{
+				"skaffold/v2beta5: build.cluster.pullSecret renamed to build.cluster.pullSecretPath",
+				"skaffold/v2beta5: build.artifacts.buildpack renamed to build.artifacts.buildpacks",
+			},
+		},
+		{
+			description: "upgrade from the last v1 schema to latest lists the manifests move",
+			from:        latestV1.Version,
+			to:          latest.Version,
+			expected: []string{
+				"skaffold/v3alpha1: deploy.kubectl.manifests moved to manifests.rawYaml",
+				"skaffold/v3alpha1: deploy.kustomize.paths moved to manifests.kustomize.paths",
+				"skaffold/v3alpha1: deploy.kustomize.buildArgs moved to manifests.kustomize.buildArgs",
+				"skaffold/v3alpha1: deploy.helm releases moved to manifests.helm",
+			},
+		},
+		{
+			description: "no-op upgrade has an empty changelog",
+			from:        v2beta4.Version,
+			to:          v2beta4.Version,
+		},
+		{
+			description: "downgrades are rejected",
+			from:        latest.Version,
+			to:          v2beta4.Version,
+			shouldErr:   true,
+		},
+		{
+			description: "unknown version",
+			from:        "skaffold/v9000",
+			to:          latest.Version,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			changelog, err := UpgradeChangelog(test.from, test.to)
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expected, changelog)
+		})
+	}
+}
@@ -19,6 +19,9 @@ package defaults
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mitchellh/go-homedir"
@@ -30,6 +33,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	schemautil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 )
 
 const (
@@ -109,10 +113,85 @@ func Set(c *latest.SkaffoldConfig) error {
 		setDefaultAddress(pf)
 	}
 
+	if err := normalizeDurations(c); err != nil {
+		return err
+	}
+
+	if err := validateCloudRunFields(c); err != nil {
+		return err
+	}
+
 	setDefaultTestWorkspace(c)
 	return nil
 }
 
+// gcpProjectPattern and gcpRegionPattern encode the shape of GCP project ids
+// and region names, so typos surface at parse time instead of as a confusing
+// `projects//locations/` string during the deploy.
+var (
+	gcpProjectPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+	gcpRegionPattern  = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+)
+
+// validateCloudRunFields checks the Cloud Run deploy's project and region at
+// parse time. An empty project is legal (it may come from the service
+// manifest) and so is an empty region (it may come from the gcloud
+// configuration or the --cloud-run-location flag), so those only warn; fields
+// that are set but malformed are errors naming the field.
+func validateCloudRunFields(c *latest.SkaffoldConfig) error {
+	crDeploy := c.Deploy.CloudRunDeploy
+	if crDeploy == nil {
+		return nil
+	}
+	if crDeploy.ProjectID != "" && !gcpProjectPattern.MatchString(crDeploy.ProjectID) {
+		return fmt.Errorf("invalid cloudrun.projectid %q: expected a Google Cloud project id like my-project", crDeploy.ProjectID)
+	}
+	for _, field := range []struct{ name, value string }{
+		{"cloudrun.region", crDeploy.Region},
+		{"cloudrun.defaultRegion", crDeploy.DefaultRegion},
+	} {
+		if field.value != "" && !gcpRegionPattern.MatchString(field.value) {
+			return fmt.Errorf("invalid %s %q: expected a region like us-central1", field.name, field.value)
+		}
+	}
+	if crDeploy.ProjectID == "" {
+		warnings.Printf("cloudrun deploy has no projectid: the Google Cloud project must then be set in the Cloud Run service manifest")
+	}
+	if crDeploy.Region == "" && crDeploy.DefaultRegion == "" {
+		warnings.Printf("cloudrun deploy has no region: set cloudrun.region in skaffold.yaml or pass --cloud-run-location")
+	}
+	return nil
+}
+
+// normalizeDurations validates the schema's duration-like fields and rewrites
+// them to their canonical form so every consumer parses them the same way.
+func normalizeDurations(c *latest.SkaffoldConfig) error {
+	if cluster := c.Build.Cluster; cluster != nil {
+		normalized, err := schemautil.NormalizeDuration("cluster.timeout", cluster.Timeout)
+		if err != nil {
+			return err
+		}
+		cluster.Timeout = normalized
+	}
+	if gcb := c.Build.GoogleCloudBuild; gcb != nil && gcb.Timeout != "" {
+		if _, err := schemautil.NormalizeDuration("googleCloudBuild.timeout", gcb.Timeout); err != nil {
+			return err
+		}
+		// Cloud Build's API only accepts the seconds form, so the value is
+		// rendered as seconds instead of Go's mixed-unit canonical form.
+		d, _ := time.ParseDuration(gcb.Timeout)
+		gcb.Timeout = fmt.Sprintf("%ds", int64(d.Seconds()))
+	}
+	if cr := c.Deploy.CloudRunDeploy; cr != nil {
+		normalized, err := schemautil.NormalizeDuration("cloudrun.readinessTimeout", cr.ReadinessTimeout)
+		if err != nil {
+			return err
+		}
+		cr.ReadinessTimeout = normalized
+	}
+	return nil
+}
+
 // SetDefaultRenderer sets the default manifests to rawYaml.
 func SetDefaultRenderer(c *latest.SkaffoldConfig) {
 	if len(c.Render.Generate.Kpt) > 0 {
@@ -215,6 +294,28 @@ func setDefaultTagger(c *latest.SkaffoldConfig) {
 	}
 
 	c.Build.TagPolicy = latest.TagPolicy{GitTagger: &latest.GitTagger{}}
+
+	// pushing to a remote registry without an explicit tag policy is ambiguous:
+	// surface the default that was chosen.
+	for _, a := range c.Build.Artifacts {
+		if targetsRemoteRegistry(a.ImageName) {
+			warnings.Printf("no tagPolicy set for image '%s' pushed to a remote registry: defaulting to the git tagger", a.ImageName)
+			break
+		}
+	}
+}
+
+// targetsRemoteRegistry reports whether the image name points at a remote
+// registry rather than the local daemon or a local registry.
+func targetsRemoteRegistry(image string) bool {
+	domain, _, found := strings.Cut(image, "/")
+	if !found {
+		return false
+	}
+	if !strings.ContainsAny(domain, ".:") {
+		return false
+	}
+	return !strings.HasPrefix(domain, "localhost")
 }
 
 func setDefaultLogsConfig(c *latest.SkaffoldConfig) {
@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	schemautil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -177,7 +178,8 @@ func TestSetDefaultsOnCluster(t *testing.T) {
 
 		t.CheckNoError(err)
 		t.CheckDeepEqual("ns", cfg.Build.Cluster.Namespace)
-		t.CheckDeepEqual(kaniko.DefaultTimeout, cfg.Build.Cluster.Timeout)
+		// the default timeout is normalized to its canonical form.
+		t.CheckDeepEqual("20m0s", cfg.Build.Cluster.Timeout)
 
 		// artifact types
 		t.CheckNotNil(cfg.Pipeline.Build.Artifacts[0].KanikoArtifact)
@@ -299,6 +301,148 @@ func TestSetDefaultsOnCloudBuild(t *testing.T) {
 	testutil.CheckDeepEqual(t, defaultCloudBuildPackImage, cfg.Build.GoogleCloudBuild.PackImage)
 }
 
+func TestNormalizeDurations(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         *latest.SkaffoldConfig
+		check       func(t *testutil.T, cfg *latest.SkaffoldConfig)
+		shouldErr   bool
+	}{
+		{
+			description: "cluster timeout is canonicalized",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						BuildType: latest.BuildType{
+							Cluster: &latest.ClusterDetails{Timeout: "90m"},
+						},
+					},
+				},
+			},
+			check: func(t *testutil.T, cfg *latest.SkaffoldConfig) {
+				t.CheckDeepEqual("1h30m0s", cfg.Build.Cluster.Timeout)
+			},
+		},
+		{
+			description: "cloud build timeout is rendered as seconds",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						BuildType: latest.BuildType{
+							GoogleCloudBuild: &latest.GoogleCloudBuild{Timeout: "20m"},
+						},
+					},
+				},
+			},
+			check: func(t *testutil.T, cfg *latest.SkaffoldConfig) {
+				t.CheckDeepEqual("1200s", cfg.Build.GoogleCloudBuild.Timeout)
+			},
+		},
+		{
+			description: "cloud run readiness timeout is canonicalized",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Deploy: latest.DeployConfig{
+						DeployType: latest.DeployType{
+							CloudRunDeploy: &latest.CloudRunDeploy{ReadinessTimeout: "90s"},
+						},
+					},
+				},
+			},
+			check: func(t *testutil.T, cfg *latest.SkaffoldConfig) {
+				t.CheckDeepEqual("1m30s", cfg.Deploy.CloudRunDeploy.ReadinessTimeout)
+			},
+		},
+		{
+			description: "invalid duration errors",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						BuildType: latest.BuildType{
+							Cluster: &latest.ClusterDetails{Timeout: "soon"},
+						},
+					},
+				},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			err := Set(test.cfg)
+
+			t.CheckError(test.shouldErr, err)
+			if test.check != nil {
+				test.check(t, test.cfg)
+			}
+		})
+	}
+}
+
+func TestValidateCloudRunFields(t *testing.T) {
+	cloudRunConfig := func(crDeploy *latest.CloudRunDeploy) *latest.SkaffoldConfig {
+		return &latest.SkaffoldConfig{
+			Pipeline: latest.Pipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						CloudRunDeploy: crDeploy,
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		description      string
+		cfg              *latest.SkaffoldConfig
+		errContains      string
+		expectedWarnings []string
+	}{
+		{
+			description: "fully specified cloud run deploy",
+			cfg:         cloudRunConfig(&latest.CloudRunDeploy{ProjectID: "my-project", Region: "us-central1"}),
+		},
+		{
+			description: "missing project warns naming the manifest fallback",
+			cfg:         cloudRunConfig(&latest.CloudRunDeploy{Region: "us-central1"}),
+			expectedWarnings: []string{
+				"cloudrun deploy has no projectid: the Google Cloud project must then be set in the Cloud Run service manifest",
+			},
+		},
+		{
+			description: "missing region warns naming the flag fallback",
+			cfg:         cloudRunConfig(&latest.CloudRunDeploy{ProjectID: "my-project"}),
+			expectedWarnings: []string{
+				"cloudrun deploy has no region: set cloudrun.region in skaffold.yaml or pass --cloud-run-location",
+			},
+		},
+		{
+			description: "malformed project id errors",
+			cfg:         cloudRunConfig(&latest.CloudRunDeploy{ProjectID: "projects/my-project", Region: "us-central1"}),
+			errContains: "cloudrun.projectid",
+		},
+		{
+			description: "malformed region errors",
+			cfg:         cloudRunConfig(&latest.CloudRunDeploy{ProjectID: "my-project", Region: "us central1"}),
+			errContains: "cloudrun.region",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			err := Set(test.cfg)
+
+			if test.errContains != "" {
+				t.CheckErrorContains(test.errContains, err)
+				return
+			}
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
 func TestSetDefaultsOnLocalBuild(t *testing.T) {
 	cfg1 := &latest.SkaffoldConfig{Pipeline: latest.Pipeline{Build: latest.BuildConfig{}}}
 	cfg2 := &latest.SkaffoldConfig{Pipeline: latest.Pipeline{Build: latest.BuildConfig{Artifacts: []*latest.Artifact{{ImageName: "foo"}}}}}
@@ -513,3 +657,57 @@ func TestSetDefaultRenderer(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDefaultTaggerWarnsOnRemoteRegistry(t *testing.T) {
+	tests := []struct {
+		description      string
+		tagPolicy        latest.TagPolicy
+		imageName        string
+		expectedWarnings []string
+	}{
+		{
+			description: "remote registry image without a tag policy",
+			imageName:   "gcr.io/project/app",
+			expectedWarnings: []string{
+				"no tagPolicy set for image 'gcr.io/project/app' pushed to a remote registry: defaulting to the git tagger",
+			},
+		},
+		{
+			description: "local image without a tag policy",
+			imageName:   "app",
+		},
+		{
+			description: "local registry image without a tag policy",
+			imageName:   "localhost:5000/app",
+		},
+		{
+			description: "remote registry image with an explicit tag policy",
+			tagPolicy:   latest.TagPolicy{ShaTagger: &latest.ShaTagger{}},
+			imageName:   "gcr.io/project/app",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			cfg := &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						TagPolicy: test.tagPolicy,
+						Artifacts: []*latest.Artifact{{ImageName: test.imageName}},
+					},
+				},
+			}
+			err := Set(cfg)
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+			if test.tagPolicy == (latest.TagPolicy{}) {
+				t.CheckDeepEqual(latest.TagPolicy{GitTagger: &latest.GitTagger{}}, cfg.Build.TagPolicy)
+			} else {
+				t.CheckDeepEqual(test.tagPolicy, cfg.Build.TagPolicy)
+			}
+		})
+	}
+}
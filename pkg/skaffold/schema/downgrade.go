@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+)
+
+// DowngradeTo converts configs to an older schema version, for pipelines pinned
+// to an older Skaffold binary. It walks the known versions in reverse one step
+// at a time, re-decoding the config with each older schema. Fields with no
+// representation in the older version are dropped and reported in the returned
+// warnings rather than silently lost; configs the older schema cannot represent
+// at all produce an error.
+func DowngradeTo(cfgs []util.VersionedConfig, targetVersion string) ([]util.VersionedConfig, []string, error) {
+	targetIndex, err := versionIndex(targetVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	var downgraded []util.VersionedConfig
+	var warnings []string
+	for _, cfg := range cfgs {
+		currentIndex, err := versionIndex(cfg.GetVersion())
+		if err != nil {
+			return nil, nil, err
+		}
+		if currentIndex < targetIndex {
+			return nil, nil, fmt.Errorf("config version %q is older than target version %q: use UpgradeTo", cfg.GetVersion(), targetVersion)
+		}
+		for i := currentIndex; i > targetIndex; i-- {
+			previous := AllVersions[i-1]
+			cfg, err = downgradeStep(cfg, previous, &warnings)
+			if err != nil {
+				return nil, nil, fmt.Errorf("downgrading config to version %q: %w", previous.APIVersion, err)
+			}
+		}
+		downgraded = append(downgraded, cfg)
+	}
+	return downgraded, warnings, nil
+}
+
+// downgradeStep re-decodes cfg with the next older schema version, recording a
+// warning for every field the older version does not know about.
+func downgradeStep(cfg util.VersionedConfig, previous Version, warnings *[]string) (util.VersionedConfig, error) {
+	buf, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal config: %w", err)
+	}
+	// rewrite the apiVersion so the older config type reports the right version.
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+	doc["apiVersion"] = previous.APIVersion
+	buf, err = yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal config: %w", err)
+	}
+
+	downgraded := previous.Factory()
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	decoder.KnownFields(true)
+	err = decoder.Decode(downgraded)
+	if err == nil || err == io.EOF {
+		return downgraded, nil
+	}
+	typeErr := &yaml.TypeError{}
+	if !errors.As(err, &typeErr) {
+		return nil, err
+	}
+	// unknown-field errors mean the field is dropped in the older version: decode
+	// leniently and surface what was lost; anything else has no downgrade mapping.
+	for _, detail := range typeErr.Errors {
+		if !strings.Contains(detail, "not found in type") {
+			return nil, fmt.Errorf("no downgrade mapping: %s", detail)
+		}
+		*warnings = append(*warnings, fmt.Sprintf("version %q: dropped %s", previous.APIVersion, detail))
+	}
+	downgraded = previous.Factory()
+	if err := yaml.Unmarshal(buf, downgraded); err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+	return downgraded, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta4"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta5"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDowngradeTo(t *testing.T) {
+	testutil.Run(t, "downgrade without dropped fields", func(t *testutil.T) {
+		cfg := v2beta5.NewSkaffoldConfig()
+		err := yaml.Unmarshal([]byte("apiVersion: "+v2beta5.Version+"\nkind: Config\nbuild:\n  artifacts:\n  - image: img\n"), cfg)
+		t.CheckNoError(err)
+
+		downgraded, warnings, err := DowngradeTo([]util.VersionedConfig{cfg}, v2beta4.Version)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(0, len(warnings))
+		t.CheckDeepEqual(v2beta4.Version, downgraded[0].GetVersion())
+	})
+
+	testutil.Run(t, "fields unknown to the target are dropped with warnings", func(t *testutil.T) {
+		cfg := v2beta5.NewSkaffoldConfig()
+		err := yaml.Unmarshal([]byte("apiVersion: "+v2beta5.Version+"\nkind: Config\nbuild:\n  cluster:\n    pullSecretPath: /secret\n"), cfg)
+		t.CheckNoError(err)
+
+		downgraded, warnings, err := DowngradeTo([]util.VersionedConfig{cfg}, v2beta4.Version)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(v2beta4.Version, downgraded[0].GetVersion())
+		if len(warnings) == 0 || !strings.Contains(warnings[0], "pullSecretPath") {
+			t.Fatalf("expected a warning about the dropped pullSecretPath field, got %v", warnings)
+		}
+	})
+
+	testutil.Run(t, "downgrading to a newer version is rejected", func(t *testutil.T) {
+		cfg := v2beta4.NewSkaffoldConfig()
+		err := yaml.Unmarshal([]byte("apiVersion: "+v2beta4.Version+"\nkind: Config\n"), cfg)
+		t.CheckNoError(err)
+
+		_, _, err = DowngradeTo([]util.VersionedConfig{cfg}, v2beta5.Version)
+
+		t.CheckErrorContains("use UpgradeTo", err)
+	})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// volatileLabels are label and annotation keys that change on every Skaffold
+// run without changing what gets deployed, and so must not influence the
+// fingerprint.
+var volatileLabels = []string{
+	"skaffold.dev/run-id",
+	"run-id",
+}
+
+// StableFingerprint returns a hex-encoded hash of the config that ignores
+// volatile metadata such as run-ID labels, so semantically identical deploys
+// share a fingerprint and can be skipped.
+func StableFingerprint(cfg *latest.SkaffoldConfig) (string, error) {
+	buf, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal config for fingerprinting: %w", err)
+	}
+	clone := &latest.SkaffoldConfig{}
+	if err := yaml.Unmarshal(buf, clone); err != nil {
+		return "", fmt.Errorf("unable to clone config for fingerprinting: %w", err)
+	}
+	for _, key := range volatileLabels {
+		delete(clone.Metadata.Labels, key)
+		delete(clone.Metadata.Annotations, key)
+	}
+	stable, err := yaml.Marshal(clone)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal config for fingerprinting: %w", err)
+	}
+	digest := sha256.Sum256(stable)
+	return hex.EncodeToString(digest[:]), nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestStableFingerprint(t *testing.T) {
+	baseConfig := func() *latest.SkaffoldConfig {
+		return &latest.SkaffoldConfig{
+			APIVersion: latest.Version,
+			Kind:       "Config",
+			Metadata: latest.Metadata{
+				Name:   "test",
+				Labels: map[string]string{"team": "backend"},
+			},
+			Pipeline: latest.Pipeline{
+				Build: latest.BuildConfig{
+					Artifacts: []*latest.Artifact{{ImageName: "image1"}},
+				},
+			},
+		}
+	}
+
+	testutil.Run(t, "run-id labels do not change the fingerprint", func(t *testutil.T) {
+		withRunID := baseConfig()
+		withRunID.Metadata.Labels["skaffold.dev/run-id"] = "1234-5678"
+		withoutRunID := baseConfig()
+
+		first, err := StableFingerprint(withRunID)
+		t.CheckNoError(err)
+		second, err := StableFingerprint(withoutRunID)
+		t.CheckNoError(err)
+
+		t.CheckDeepEqual(first, second)
+	})
+
+	testutil.Run(t, "semantic changes produce a different fingerprint", func(t *testutil.T) {
+		changed := baseConfig()
+		changed.Build.Artifacts[0].ImageName = "image2"
+
+		first, err := StableFingerprint(baseConfig())
+		t.CheckNoError(err)
+		second, err := StableFingerprint(changed)
+		t.CheckNoError(err)
+
+		if first == second {
+			t.Fatalf("expected different fingerprints but both were %s", first)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// RequiredIAMRoles returns the minimum set of Google Cloud IAM roles the builders and
+// deployers of a config need, for pre-flight permission checks. The returned list is
+// sorted and deduplicated.
+func RequiredIAMRoles(cfg *latest.SkaffoldConfig) []string {
+	roles := make(map[string]bool)
+	if cfg.Deploy.CloudRunDeploy != nil {
+		// deploying a service and running it as its runtime service account.
+		roles["roles/run.admin"] = true
+		roles["roles/iam.serviceAccountUser"] = true
+	}
+	if cfg.Build.GoogleCloudBuild != nil {
+		// submitting builds and staging sources in the Cloud Storage bucket.
+		roles["roles/cloudbuild.builds.editor"] = true
+		roles["roles/storage.admin"] = true
+	}
+
+	var sorted []string
+	for role := range roles {
+		sorted = append(sorted, role)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestRequiredIAMRoles(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         *latest.SkaffoldConfig
+		expected    []string
+	}{
+		{
+			description: "cloud run deploy",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Deploy: latest.DeployConfig{
+						DeployType: latest.DeployType{
+							CloudRunDeploy: &latest.CloudRunDeploy{},
+						},
+					},
+				},
+			},
+			expected: []string{"roles/iam.serviceAccountUser", "roles/run.admin"},
+		},
+		{
+			description: "gcb build",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						BuildType: latest.BuildType{
+							GoogleCloudBuild: &latest.GoogleCloudBuild{},
+						},
+					},
+				},
+			},
+			expected: []string{"roles/cloudbuild.builds.editor", "roles/storage.admin"},
+		},
+		{
+			description: "local build with kubectl deploy needs no GCP roles",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						BuildType: latest.BuildType{
+							LocalBuild: &latest.LocalBuild{},
+						},
+					},
+					Deploy: latest.DeployConfig{
+						DeployType: latest.DeployType{
+							KubectlDeploy: &latest.KubectlDeploy{},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			t.CheckDeepEqual(test.expected, RequiredIAMRoles(test.cfg))
+		})
+	}
+}
@@ -751,6 +751,254 @@ type CloudRunDeploy struct {
 	// Region GCP location to use for the Cloud Run Deploy.
 	// Must be one of the regions listed in https://cloud.google.com/run/docs/locations.
 	Region string `yaml:"region,omitempty"`
+
+	// DefaultRegion the region used when `region` is not set here, in an active
+	// profile, or in the gcloud configuration (`gcloud config get-value run/region`).
+	DefaultRegion string `yaml:"defaultRegion,omitempty"`
+
+	// ContainerDependencies the container startup order for multi-container Services,
+	// keyed by container name. Each container starts after the listed containers are
+	// ready. Populates the `run.googleapis.com/container-dependencies` annotation on
+	// the deployed revision.
+	// For example: `{"app": ["init"]}`.
+	ContainerDependencies map[string][]string `yaml:"containerDependencies,omitempty"`
+
+	// CredentialsFile path to a Google Cloud service account key used to
+	// authenticate Cloud Run API calls instead of application default credentials.
+	// Useful when deploying across accounts from CI.
+	CredentialsFile string `yaml:"credentialsFile,omitempty"`
+
+	// ChangeCause a free-form description of why the deploy happened, recorded as
+	// an annotation on the deployed revision. Defaults to the subject of the
+	// current git commit when left unset.
+	ChangeCause string `yaml:"changeCause,omitempty"`
+
+	// Description a human-readable description of the service, recorded as the
+	// `run.googleapis.com/description` annotation and shown in the Cloud console.
+	Description string `yaml:"description,omitempty"`
+
+	// LaunchStage the Cloud Run launch stage required by the features the service
+	// uses, set as the `run.googleapis.com/launch-stage` annotation. Valid values
+	// are `ALPHA`, `BETA` and `GA`.
+	LaunchStage string `yaml:"launchStage,omitempty"`
+
+	// UseHTTP2 enables end-to-end HTTP/2 by naming the container port `h2c`,
+	// per Cloud Run's convention.
+	UseHTTP2 bool `yaml:"useHTTP2,omitempty"`
+
+	// ContainerConcurrency the maximum number of concurrent requests served by
+	// one instance of the revision. When `0`, Cloud Run's default applies.
+	// Typically patched per environment through profiles.
+	ContainerConcurrency int `yaml:"containerConcurrency,omitempty"`
+
+	// MinInstances the minimum number of instances kept running for the revision,
+	// set as the `autoscaling.knative.dev/minScale` annotation.
+	MinInstances int `yaml:"minInstances,omitempty"`
+
+	// MaxInstances the maximum number of instances the revision scales out to,
+	// set as the `autoscaling.knative.dev/maxScale` annotation. When `0`, no
+	// limit is applied.
+	MaxInstances int `yaml:"maxInstances,omitempty"`
+
+	// WaitForDeletion makes `skaffold delete` poll until the Service is fully
+	// deleted instead of returning as soon as the delete request is accepted, so
+	// scripted teardown can rely on cleanup having completed.
+	WaitForDeletion bool `yaml:"waitForDeletion,omitempty"`
+
+	// ForceDelete makes `skaffold delete` disable Cloud Run deletion protection
+	// on the Service before deleting it, instead of failing when protection is
+	// enabled.
+	ForceDelete bool `yaml:"forceDelete,omitempty"`
+
+	// MaxRevisions the number of revisions of a Service to retain after a deploy.
+	// Older revisions beyond the limit are deleted unless they still receive
+	// traffic. When `0`, revisions are never pruned.
+	MaxRevisions int `yaml:"maxRevisions,omitempty"`
+
+	// PinDigest records the digest of each built image as an annotation on the
+	// deployed revision for auditing, even when the image is deployed by tag.
+	PinDigest bool `yaml:"pinDigest,omitempty"`
+
+	// ReadinessTimeout the maximum amount of time to wait for a deployed Service or
+	// Job to report a Ready condition, as a duration string (`2m`, `90s`). Takes
+	// precedence over the global status check deadline for Cloud Run resources.
+	ReadinessTimeout string `yaml:"readinessTimeout,omitempty"`
+
+	// Env environment variables set on the first container of every deployed
+	// revision. Variables defined here override variables of the same name from
+	// every other source.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// EnvFile path to a file of `KEY=VALUE` lines loaded into the environment of
+	// the first container. Overridden by variables of the same name in `env`.
+	EnvFile string `yaml:"envFile,omitempty"`
+
+	// SecretEnv environment variables resolved from Cloud Secret Manager, keyed
+	// by variable name with values of the form `secret-name:version`. Overridden
+	// by variables of the same name in `env` or the env file.
+	SecretEnv map[string]string `yaml:"secretEnv,omitempty"`
+
+	// EnvPrefix prefixes the names of injected environment variables (`env`,
+	// `envFile` and `secretEnv`) per container, keyed by container name. When
+	// set, variables are only injected into the named containers, letting
+	// multi-container services share variable names without collisions.
+	// For example: `{"app": "APP_", "worker": "WORKER_"}`.
+	EnvPrefix map[string]string `yaml:"envPrefix,omitempty"`
+
+	// ServiceAccount the IAM service account the Service's revisions run as,
+	// set on the revision spec. Overrides a service account named in the
+	// manifest; empty leaves the manifest untouched.
+	// For example: `my-app@my-project.iam.gserviceaccount.com`.
+	ServiceAccount string `yaml:"serviceAccount,omitempty"`
+
+	// VPCConnector the name of a Serverless VPC Access connector the Service
+	// routes outbound traffic through, set as the
+	// `run.googleapis.com/vpc-access-connector` annotation.
+	VPCConnector string `yaml:"vpcConnector,omitempty"`
+
+	// VPCEgress which outbound traffic is routed through the VPC connector.
+	// Valid values are `all-traffic` and `private-ranges-only`, set as the
+	// `run.googleapis.com/vpc-access-egress` annotation. Requires `vpcConnector`.
+	VPCEgress string `yaml:"vpcEgress,omitempty"`
+
+	// TerminationGracePeriodSeconds how long an instance is given to shut down
+	// gracefully after receiving SIGTERM, recorded on the revision template.
+	// Must be between `0` and `3600`. When `0`, Cloud Run's default applies.
+	TerminationGracePeriodSeconds int `yaml:"terminationGracePeriodSeconds,omitempty"`
+
+	// UploadRepo a Container Registry or Artifact Registry repository that built
+	// images are copied to when they are not already hosted in a registry Cloud
+	// Run can pull from.
+	// For example: `us-docker.pkg.dev/my-project/my-repo`.
+	UploadRepo string `yaml:"uploadRepo,omitempty"`
+
+	// Traffic how traffic is split across the revisions of the Service, for
+	// canary rollouts. Percents must add up to `100`. When empty, Cloud Run
+	// routes all traffic to the latest revision.
+	Traffic []CloudRunTrafficTarget `yaml:"traffic,omitempty"`
+
+	// ReadyConditionType the status condition the deploy status check waits on
+	// for Services. Valid values are `Ready`, `RoutesReady` and
+	// `ConfigurationsReady`. Defaults to `Ready`.
+	ReadyConditionType string `yaml:"readyConditionType,omitempty"`
+
+	// APIEndpoint the base URL of the Cloud Run API, for regional endpoints
+	// (`{region}-run.googleapis.com`) or Trusted Partner Cloud deployments.
+	// When unset the regional default endpoint is used.
+	APIEndpoint string `yaml:"apiEndpoint,omitempty"`
+
+	// ValidatePlatforms checks before deploying that each built image can run
+	// on managed Cloud Run, which only runs linux/amd64 images. Images built
+	// for other architectures fail the deploy with an actionable error instead
+	// of failing at runtime.
+	ValidatePlatforms bool `yaml:"validatePlatforms,omitempty"`
+
+	// ExecuteJob starts an execution of each deployed Cloud Run Job once its
+	// definition is updated. By default deploying a Job only updates the
+	// definition without running it.
+	ExecuteJob bool `yaml:"executeJob,omitempty"`
+
+	// AnnotationMergeStrategy how annotations computed from this config combine
+	// with annotations already present in the service manifest. `merge` (the
+	// default) keeps the manifest annotations and overwrites conflicting keys;
+	// `replace` discards the manifest annotations in favor of the configured
+	// ones.
+	AnnotationMergeStrategy string `yaml:"annotationMergeStrategy,omitempty"`
+
+	// RetryConfig tunes how transient Cloud Run API errors are retried.
+	RetryConfig *CloudRunRetryConfig `yaml:"retryConfig,omitempty"`
+
+	// DefaultLabels are labels stamped on every deployed service and revision,
+	// for example org or owner labels shared across a team. They sit beneath
+	// every other label source: labels from the manifest and skaffold's run-id
+	// labels win on key collision.
+	DefaultLabels map[string]string `yaml:"defaultLabels,omitempty"`
+
+	// StrictContainerLimit fails the deploy when a service defines more
+	// containers than managed Cloud Run allows, instead of only warning.
+	StrictContainerLimit bool `yaml:"strictContainerLimit,omitempty"`
+
+	// StartupProbe configures the startup probe Cloud Run uses to decide when
+	// a container has started. Applied to every container in the service.
+	StartupProbe *CloudRunStartupProbe `yaml:"startupProbe,omitempty"`
+
+	// TemplateManifests expands Cloud Run manifests as Go `text/template`
+	// templates against the process environment before they are deployed, so
+	// values like the region or project can come from environment variables.
+	// By default manifests are read as raw YAML.
+	TemplateManifests bool `yaml:"templateManifests,omitempty"`
+
+	// AppliedServiceFile is a file the service configuration sent to Cloud Run
+	// is written to after a successful deploy, for auditing or GitOps
+	// reconciliation. By default no file is written.
+	// For example: `applied-service.yaml`.
+	AppliedServiceFile string `yaml:"appliedServiceFile,omitempty"`
+
+	// BuildArtifactsURL is an HTTP(S) URL serving a build artifacts JSON as
+	// written by `skaffold build --file-output`. The listed images join the
+	// image substitution alongside locally built artifacts, which win on
+	// image-name collision. Meant for handing a build off between pipelines.
+	// For example: `https://ci.example.com/builds/latest.json`.
+	BuildArtifactsURL string `yaml:"buildArtifactsURL,omitempty"`
+}
+
+// CloudRunRetryConfig tunes the exponential backoff applied to transient
+// Cloud Run API errors.
+type CloudRunRetryConfig struct {
+	// BaseDelay the wait before the first retry, doubled after every attempt
+	// up to `maxDelay`. Given as a duration, e.g. `2s`. Defaults to `2s`.
+	BaseDelay string `yaml:"baseDelay,omitempty"`
+
+	// MaxDelay caps the backoff between retries. Given as a duration, e.g.
+	// `30s`. Defaults to `16s`.
+	MaxDelay string `yaml:"maxDelay,omitempty"`
+
+	// MaxAttempts how many times a request is tried before giving up.
+	// Defaults to `3`.
+	MaxAttempts int `yaml:"maxAttempts,omitempty"`
+}
+
+// CloudRunStartupProbe configures the startup probe set on the containers of
+// a deployed Cloud Run Service.
+type CloudRunStartupProbe struct {
+	// HTTPPath the path of an HTTP GET probe against the container port.
+	// When empty a TCP probe against the container port is used instead.
+	HTTPPath string `yaml:"httpPath,omitempty"`
+
+	// InitialDelaySeconds how long after container start the first probe runs,
+	// in seconds. Defaults to `0`.
+	InitialDelaySeconds int `yaml:"initialDelaySeconds,omitempty"`
+
+	// TimeoutSeconds how long a single probe may take, in seconds.
+	// Defaults to `1`.
+	TimeoutSeconds int `yaml:"timeoutSeconds,omitempty"`
+
+	// PeriodSeconds how often the probe runs, in seconds. Must be positive
+	// when set. Defaults to `10`.
+	PeriodSeconds int `yaml:"periodSeconds,omitempty"`
+
+	// FailureThreshold how many consecutive failures mark the container as
+	// failed to start. Must be positive when set. Defaults to `3`.
+	FailureThreshold int `yaml:"failureThreshold,omitempty"`
+}
+
+// CloudRunTrafficTarget routes a share of traffic to one revision of a
+// Cloud Run Service.
+type CloudRunTrafficTarget struct {
+	// RevisionName the name of an existing revision receiving this share of the
+	// traffic. Mutually exclusive with `latestRevision`. Because revision names
+	// are assigned by Cloud Run, a revision can only be pinned here once it
+	// exists: deploy first with `latestRevision: true`, then reference the
+	// created revision on the subsequent deploy.
+	RevisionName string `yaml:"revisionName,omitempty"`
+
+	// LatestRevision routes this share of the traffic to the revision created
+	// by the deploy. Mutually exclusive with `revisionName`.
+	LatestRevision bool `yaml:"latestRevision,omitempty"`
+
+	// Percent the percentage of traffic this revision receives, between `0`
+	// and `100`.
+	Percent int64 `yaml:"percent,omitempty"`
 }
 
 // DockerDeploy uses the `docker` CLI to create application containers in Docker.
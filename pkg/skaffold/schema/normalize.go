@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+)
+
+// NormalizeDuration validates a duration-like config value and returns its
+// canonical form (e.g. `20m` becomes `20m0s`). The implementation lives in
+// schema/util so that defaults.Set, which this package imports, can apply it
+// to the schema's duration fields as well.
+func NormalizeDuration(field, value string) (string, error) {
+	return util.NormalizeDuration(field, value)
+}
+
+// NormalizeImageReferences rewrites the artifact image names of a parsed config to their
+// canonical form, with a lowercased host and the implicit Docker Hub registry and library
+// repository made explicit (e.g. `nginx` becomes `docker.io/library/nginx`). This makes
+// image names comparable for caching and comparison purposes.
+func NormalizeImageReferences(cfg *latest.SkaffoldConfig) error {
+	for _, a := range cfg.Build.Artifacts {
+		named, err := reference.ParseNormalizedNamed(a.ImageName)
+		if err != nil {
+			return fmt.Errorf("normalizing image %q: %w", a.ImageName, err)
+		}
+		a.ImageName = strings.ToLower(reference.Domain(named)) + "/" + reference.Path(named)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNormalizeImageReferences(t *testing.T) {
+	tests := []struct {
+		description string
+		images      []string
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "bare image name gets explicit registry and repository",
+			images:      []string{"nginx"},
+			expected:    []string{"docker.io/library/nginx"},
+		},
+		{
+			description: "registry host is lowercased",
+			images:      []string{"GCR.io/project/image"},
+			expected:    []string{"gcr.io/project/image"},
+		},
+		{
+			description: "fully qualified image is unchanged",
+			images:      []string{"gcr.io/project/image"},
+			expected:    []string{"gcr.io/project/image"},
+		},
+		{
+			description: "invalid image name errors",
+			images:      []string{"invalid image name"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			cfg := &latest.SkaffoldConfig{}
+			for _, image := range test.images {
+				cfg.Build.Artifacts = append(cfg.Build.Artifacts, &latest.Artifact{ImageName: image})
+			}
+
+			err := NormalizeImageReferences(cfg)
+
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				var normalized []string
+				for _, a := range cfg.Build.Artifacts {
+					normalized = append(normalized, a.ImageName)
+				}
+				t.CheckDeepEqual(test.expected, normalized)
+			}
+		})
+	}
+}
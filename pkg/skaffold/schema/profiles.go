@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
 	skutil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util/stringslice"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yaml"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yamltags"
 )
@@ -48,6 +50,7 @@ func ApplyProfiles(c *latest.SkaffoldConfig, fieldsOverrodeByProfile map[string]
 	if err != nil {
 		return nil, nil, fmt.Errorf("finding auto-activated profiles: %w", err)
 	}
+	baseArtifacts := len(c.Build.Artifacts)
 	for _, name := range profiles {
 		profile, present := byName[name]
 		if !present {
@@ -59,6 +62,10 @@ func ApplyProfiles(c *latest.SkaffoldConfig, fieldsOverrodeByProfile map[string]
 		}
 	}
 
+	if len(profiles) > 0 && baseArtifacts > 0 && len(c.Build.Artifacts) == 0 {
+		warnings.Printf("profiles %q removed all %d build artifacts from the config: no images will be built", profiles, baseArtifacts)
+	}
+
 	// remove profiles section for run modes where profiles are already merged into the main pipeline
 	switch opts.Mode() {
 	case cfg.RunModes.Build, cfg.RunModes.Dev, cfg.RunModes.Deploy, cfg.RunModes.Debug, cfg.RunModes.Render, cfg.RunModes.Run, cfg.RunModes.Diagnose, cfg.RunModes.Delete:
@@ -466,3 +473,102 @@ func overlayProfileField(profileName, fieldName string, yamlFieldName string, fi
 		return config
 	}
 }
+
+// DetectProfileConflicts reports the field paths the named profiles set to
+// different values. Two profiles conflict on a field when each sets it, via
+// overlay or patch, and the resulting config depends on the order the
+// profiles are applied in; such combinations should be rejected rather than
+// silently resolved by activation order.
+func DetectProfileConflicts(cfg *latest.SkaffoldConfig, names []string) ([]string, error) {
+	byName := profilesByName(cfg.Profiles)
+	profiles := make([]latest.Profile, 0, len(names))
+	for _, name := range names {
+		profile, present := byName[name]
+		if !present {
+			return nil, fmt.Errorf("couldn't find profile %s", name)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	conflicts := map[string]bool{}
+	for i := 0; i < len(profiles); i++ {
+		for j := i + 1; j < len(profiles); j++ {
+			ab, err := applyProfilesInOrder(cfg, profiles[i], profiles[j])
+			if err != nil {
+				return nil, err
+			}
+			ba, err := applyProfilesInOrder(cfg, profiles[j], profiles[i])
+			if err != nil {
+				return nil, err
+			}
+			diffFieldPaths(ab, ba, "", conflicts)
+		}
+	}
+
+	var paths []string
+	for p := range conflicts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// applyProfilesInOrder applies the profiles to a copy of the config and
+// returns the resulting pipeline as a generic yaml map, so two application
+// orders can be compared field by field.
+func applyProfilesInOrder(cfg *latest.SkaffoldConfig, profiles ...latest.Profile) (interface{}, error) {
+	applied := *cfg
+	fields := map[string]configlocations.YAMLOverrideInfo{}
+	for _, profile := range profiles {
+		if err := applyProfile(&applied, fields, profile); err != nil {
+			return nil, fmt.Errorf("applying profile %q: %w", profile.Name, err)
+		}
+	}
+	buf, err := yaml.Marshal(applied.Pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var pipeline map[string]interface{}
+	if err := yaml.Unmarshal(buf, &pipeline); err != nil {
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+// diffFieldPaths records the shallowest paths at which the two pipelines
+// differ. Maps that share no keys, such as a oneOf field resolved to
+// different alternatives, are reported as the parent path.
+func diffFieldPaths(a, b interface{}, fieldPath string, conflicts map[string]bool) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	aMap, aOk := a.(map[string]interface{})
+	bMap, bOk := b.(map[string]interface{})
+	if !aOk || !bOk {
+		conflicts[fieldPath] = true
+		return
+	}
+	shared := false
+	for key := range aMap {
+		if _, present := bMap[key]; present {
+			shared = true
+			break
+		}
+	}
+	if !shared {
+		conflicts[fieldPath] = true
+		return
+	}
+	for key, aValue := range aMap {
+		if bValue, present := bMap[key]; present {
+			diffFieldPaths(aValue, bValue, fieldPath+"/"+key, conflicts)
+		} else {
+			conflicts[fieldPath+"/"+key] = true
+		}
+	}
+	for key := range bMap {
+		if _, present := aMap[key]; !present {
+			conflicts[fieldPath+"/"+key] = true
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	yamlpatch "github.com/krishicks/yaml-patch"
+	"gopkg.in/yaml.v3"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/kaniko"
@@ -29,6 +30,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/parser/configlocations"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -875,3 +877,128 @@ func setupFakeKubeConfig(t *testutil.T, config api.Config) {
 		return config, nil
 	})
 }
+
+func TestApplyProfileRemovingAllArtifacts(t *testing.T) {
+	testutil.Run(t, "profile that empties build artifacts warns", func(t *testutil.T) {
+		fakeWarner := &warnings.Collect{}
+		t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+		skaffoldConfig := config(
+			withLocalBuild(
+				withGitTagger(),
+				withDockerArtifact("image", ".", "Dockerfile"),
+			),
+			withRawK8s("k8s/*.yaml"),
+			withProfiles(latest.Profile{
+				Name: "no-build",
+				Patches: []latest.JSONPatch{{
+					Op:   "remove",
+					Path: "/build/artifacts/0",
+				}},
+			}),
+		)
+
+		_, _, err := ApplyProfiles(skaffoldConfig, map[string]configlocations.YAMLOverrideInfo{}, cfg.SkaffoldOptions{}, []string{"no-build"})
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(0, len(skaffoldConfig.Build.Artifacts))
+		t.CheckDeepEqual([]string{`profiles ["no-build"] removed all 1 build artifacts from the config: no images will be built`}, fakeWarner.Warnings)
+	})
+}
+
+func withCloudRunDeploy(region string, maxInstances int) func(*latest.SkaffoldConfig) {
+	return func(cfg *latest.SkaffoldConfig) {
+		cfg.Deploy.DeployType.CloudRunDeploy = &latest.CloudRunDeploy{Region: region, MaxInstances: maxInstances}
+	}
+}
+
+func TestApplyProfileCloudRunScaling(t *testing.T) {
+	testutil.Run(t, "profile raises max instances", func(t *testutil.T) {
+		var maxInstances interface{} = 10
+		skaffoldConfig := config(
+			withLocalBuild(withGitTagger()),
+			withCloudRunDeploy("us-central1", 2),
+			withProfiles(latest.Profile{
+				Name: "prod",
+				Patches: []latest.JSONPatch{{
+					Path:  "/deploy/cloudrun/maxInstances",
+					Value: &util.YamlpatchNode{Node: *yamlpatch.NewNode(&maxInstances)},
+				}},
+			}),
+		)
+
+		_, _, err := ApplyProfiles(skaffoldConfig, map[string]configlocations.YAMLOverrideInfo{}, cfg.SkaffoldOptions{}, []string{"prod"})
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(10, skaffoldConfig.Deploy.CloudRunDeploy.MaxInstances)
+
+		// the merged config must serialize and parse back unchanged.
+		buf, err := yaml.Marshal(skaffoldConfig)
+		t.CheckNoError(err)
+		parsed := &latest.SkaffoldConfig{}
+		t.CheckNoError(yaml.Unmarshal(buf, parsed))
+		t.CheckDeepEqual(10, parsed.Deploy.CloudRunDeploy.MaxInstances)
+	})
+}
+
+func TestDetectProfileConflicts(t *testing.T) {
+	config := `build:
+  artifacts:
+  - image: example
+profiles:
+- name: sha
+  build:
+    tagPolicy:
+      sha256: {}
+- name: commit
+  build:
+    tagPolicy:
+      gitCommit: {}
+- name: renamed
+  patches:
+  - path: /build/artifacts/0/image
+    value: renamed
+`
+	tests := []struct {
+		description string
+		profiles    []string
+		expected    []string
+		shouldErr   bool
+	}{
+		{
+			description: "profiles setting the tag policy differently conflict",
+			profiles:    []string{"sha", "commit"},
+			expected:    []string{"/build/tagPolicy"},
+		},
+		{
+			description: "profiles touching different fields do not conflict",
+			profiles:    []string{"sha", "renamed"},
+			expected:    nil,
+		},
+		{
+			description: "a profile alone has nothing to conflict with",
+			profiles:    []string{"commit"},
+			expected:    nil,
+		},
+		{
+			description: "unknown profile",
+			profiles:    []string{"sha", "missing"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				Write("skaffold.yaml", addVersion(config))
+
+			parsed, err := ParseConfig(tmpDir.Path("skaffold.yaml"))
+			t.CheckNoError(err)
+			t.CheckTrue(len(parsed) > 0)
+
+			skaffoldConfig := parsed[0].(*latest.SkaffoldConfig)
+			conflicts, err := DetectProfileConflicts(skaffoldConfig, test.profiles)
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, conflicts)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"reflect"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/defaults"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// StripDefaults removes from cfg's pipeline every value that defaults.Set would
+// produce for an otherwise empty config, yielding a minimal config suitable for
+// diffing or storage. It is the inverse of defaults.Set for defaulted fields.
+func StripDefaults(cfg *latest.SkaffoldConfig) error {
+	defaulted := &latest.SkaffoldConfig{
+		APIVersion: cfg.APIVersion,
+		Kind:       cfg.Kind,
+		Metadata:   cfg.Metadata,
+	}
+	if err := defaults.Set(defaulted); err != nil {
+		return err
+	}
+	stripStruct(reflect.ValueOf(&cfg.Pipeline).Elem(), reflect.ValueOf(&defaulted.Pipeline).Elem())
+	return nil
+}
+
+// stripStruct zeroes every field of value that deep-equals the corresponding
+// defaulted field, recursing into structs and non-nil struct pointers so that
+// only genuinely user-set values survive.
+func stripStruct(value reflect.Value, defaulted reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		defaultedField := defaulted.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if reflect.DeepEqual(field.Interface(), defaultedField.Interface()) {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Struct:
+			stripStruct(field, defaultedField)
+		case reflect.Ptr:
+			if !field.IsNil() && !defaultedField.IsNil() && field.Elem().Kind() == reflect.Struct {
+				stripStruct(field.Elem(), defaultedField.Elem())
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/defaults"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestStripDefaults(t *testing.T) {
+	testutil.Run(t, "defaulted minimal config strips back to the minimal input", func(t *testutil.T) {
+		cfg := &latest.SkaffoldConfig{
+			APIVersion: latest.Version,
+			Kind:       "Config",
+		}
+		t.CheckNoError(defaults.Set(cfg))
+
+		t.CheckNoError(StripDefaults(cfg))
+
+		t.CheckDeepEqual(latest.SkaffoldConfig{
+			APIVersion: latest.Version,
+			Kind:       "Config",
+		}, *cfg)
+	})
+
+	testutil.Run(t, "user-set values survive stripping", func(t *testutil.T) {
+		cfg := &latest.SkaffoldConfig{
+			APIVersion: latest.Version,
+			Kind:       "Config",
+			Pipeline: latest.Pipeline{
+				Deploy: latest.DeployConfig{
+					DeployType: latest.DeployType{
+						CloudRunDeploy: &latest.CloudRunDeploy{Region: "us-central1"},
+					},
+				},
+			},
+		}
+		t.CheckNoError(defaults.Set(cfg))
+
+		t.CheckNoError(StripDefaults(cfg))
+
+		t.CheckDeepEqual(latest.DeployConfig{
+			DeployType: latest.DeployType{
+				CloudRunDeploy: &latest.CloudRunDeploy{Region: "us-central1"},
+			},
+		}, cfg.Deploy)
+	})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/apiversion"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yaml"
+)
+
+// DirectoryUpgradeResult summarizes an UpgradeDirectory run: the files that
+// were rewritten, the files skipped because they are already at or beyond the
+// target version, and the per-file errors collected along the way.
+type DirectoryUpgradeResult struct {
+	Upgraded []string
+	Skipped  []string
+	Failed   map[string]error
+}
+
+// UpgradeDirectory walks root, upgrades every skaffold config found below it
+// to targetVersion and rewrites the files in place. Files already at or
+// beyond the target version are skipped, and a failing file does not abort
+// the walk: its error is collected in the result instead.
+func UpgradeDirectory(root string, targetVersion string) (*DirectoryUpgradeResult, error) {
+	if _, err := apiversion.Parse(targetVersion); err != nil {
+		return nil, fmt.Errorf("target version %q is invalid: %w", targetVersion, err)
+	}
+	result := &DirectoryUpgradeResult{Failed: map[string]error{}}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		if !IsSkaffoldConfig(path) {
+			return nil
+		}
+		changed, err := upgradeConfigFile(path, targetVersion)
+		switch {
+		case err != nil:
+			result.Failed[path] = err
+		case changed:
+			result.Upgraded = append(result.Upgraded, path)
+		default:
+			result.Skipped = append(result.Skipped, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpgradeWithDiff upgrades cfg to the next schema version, like Upgrade, and
+// also returns a unified diff of the config's yaml before and after, so the
+// structural changes of an upgrade can be previewed before rewriting files.
+// The yaml rendering follows the schema's field order, so the diff is stable
+// across runs and can be asserted in tests.
+func UpgradeWithDiff(cfg util.VersionedConfig) (util.VersionedConfig, string, error) {
+	before, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshaling config before upgrade: %w", err)
+	}
+	upgraded, err := cfg.Upgrade()
+	if err != nil {
+		return nil, "", err
+	}
+	after, err := yaml.Marshal(upgraded)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshaling upgraded config: %w", err)
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: cfg.GetVersion(),
+		ToFile:   upgraded.GetVersion(),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("computing upgrade diff: %w", err)
+	}
+	return upgraded, diff, nil
+}
+
+// UpgradePath returns the ordered apiVersions an upgrade from `from` to `to`
+// walks through, both endpoints included. It is derived from the supported
+// versions slice, so it always matches the sequence UpgradeTo actually
+// applies. A `to` older than `from` or an unknown version is an error.
+func UpgradePath(from, to string) ([]string, error) {
+	versions := SupportedVersions()
+	fromIndex, toIndex := -1, -1
+	for i, version := range versions {
+		if version == from {
+			fromIndex = i
+		}
+		if version == to {
+			toIndex = i
+		}
+	}
+	if fromIndex < 0 {
+		return nil, fmt.Errorf("unknown api version %q", from)
+	}
+	if toIndex < 0 {
+		return nil, fmt.Errorf("unknown api version %q", to)
+	}
+	if toIndex < fromIndex {
+		return nil, fmt.Errorf("version %q is more recent than target version %q", from, to)
+	}
+	return versions[fromIndex : toIndex+1], nil
+}
+
+// upgradeConfigFile upgrades the configs in one file to targetVersion and
+// rewrites the file, reporting whether anything changed.
+func upgradeConfigFile(path string, targetVersion string) (bool, error) {
+	target, err := apiversion.Parse(targetVersion)
+	if err != nil {
+		return false, err
+	}
+	cfgs, err := ParseConfig(path)
+	if err != nil {
+		return false, err
+	}
+	needed := false
+	for _, cfg := range cfgs {
+		current, err := apiversion.Parse(cfg.GetVersion())
+		if err != nil {
+			return false, err
+		}
+		if current.LT(target) {
+			needed = true
+		}
+	}
+	if !needed {
+		return false, nil
+	}
+	upgraded, err := UpgradeTo(cfgs, targetVersion)
+	if err != nil {
+		return false, err
+	}
+	buf, err := yaml.MarshalWithSeparator(upgraded)
+	if err != nil {
+		return false, fmt.Errorf("marshaling upgraded config: %w", err)
+	}
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		return false, fmt.Errorf("writing config file: %w", err)
+	}
+	return true, nil
+}
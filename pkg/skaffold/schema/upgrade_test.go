@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	latestV1 "github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/v2beta29"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestUpgradeDirectory(t *testing.T) {
+	oldConfig := fmt.Sprintf("apiVersion: skaffold/v1beta6\nkind: Config\n%s", simpleConfig)
+	currentConfig := fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, simpleConfig)
+
+	testutil.Run(t, "configs below the target are rewritten, others skipped", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", oldConfig).
+			Write("current/skaffold.yaml", currentConfig).
+			Write("other.yaml", "not: a skaffold config\n").
+			Write("notes.txt", oldConfig)
+
+		result, err := UpgradeDirectory(tmpDir.Root(), latest.Version)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{tmpDir.Path("skaffold.yaml")}, result.Upgraded)
+		t.CheckDeepEqual([]string{tmpDir.Path("current/skaffold.yaml")}, result.Skipped)
+		t.CheckDeepEqual(0, len(result.Failed))
+
+		// the rewritten file parses at the target version.
+		cfgs, err := ParseConfig(tmpDir.Path("skaffold.yaml"))
+		t.CheckNoError(err)
+		t.CheckDeepEqual(latest.Version, cfgs[0].GetVersion())
+	})
+	testutil.Run(t, "files beyond the target are skipped", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", currentConfig)
+
+		result, err := UpgradeDirectory(tmpDir.Root(), latestV1.Version)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(0, len(result.Upgraded))
+		t.CheckDeepEqual([]string{tmpDir.Path("skaffold.yaml")}, result.Skipped)
+	})
+	testutil.Run(t, "a failing file does not abort the run", func(t *testutil.T) {
+		// the mixed file needs an upgrade but holds a config beyond the
+		// target, so upgrading it fails.
+		tmpDir := t.NewTempDir().
+			Write("mixed/skaffold.yaml", fmt.Sprintf("%s\n---\n%s", oldConfig, currentConfig)).
+			Write("old/skaffold.yaml", oldConfig)
+
+		result, err := UpgradeDirectory(tmpDir.Root(), latestV1.Version)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual([]string{tmpDir.Path("old/skaffold.yaml")}, result.Upgraded)
+		t.CheckDeepEqual(1, len(result.Failed))
+		t.CheckErrorContains("more recent than target", result.Failed[tmpDir.Path("mixed/skaffold.yaml")])
+	})
+	testutil.Run(t, "unknown fields fail the parse instead of being dropped", func(t *testutil.T) {
+		// configs are decoded with KnownFields, so a key the source schema
+		// does not know can never be silently lost by an upgrade: the parse
+		// itself reports it.
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", fmt.Sprintf("%scustomTopLevelKey: keep-me\n", oldConfig))
+
+		_, err := ParseConfig(tmpDir.Path("skaffold.yaml"))
+
+		t.CheckErrorContains("customTopLevelKey", err)
+	})
+	testutil.Run(t, "invalid target version", func(t *testutil.T) {
+		tmpDir := t.NewTempDir()
+
+		_, err := UpgradeDirectory(tmpDir.Root(), "skaffold/vNope")
+
+		t.CheckErrorContains("is invalid", err)
+	})
+}
+
+func TestUpgradeToLatestSentinel(t *testing.T) {
+	testutil.Run(t, "v1 source resolves to the v1 track's latest", func(t *testutil.T) {
+		cfgs, err := ParseConfigFromReader(strings.NewReader(fmt.Sprintf("apiVersion: skaffold/v1beta6\nkind: Config\n%s", simpleConfig)))
+		t.CheckNoError(err)
+
+		upgraded, err := UpgradeTo(cfgs, LatestTarget)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(latestV1.Version, upgraded[0].GetVersion())
+	})
+	testutil.Run(t, "v2 source resolves to the binary's latest", func(t *testutil.T) {
+		cfgs, err := ParseConfigFromReader(strings.NewReader(fmt.Sprintf("apiVersion: skaffold/v3\nkind: Config\n%s", simpleConfig)))
+		t.CheckNoError(err)
+
+		upgraded, err := UpgradeTo(cfgs, LatestTarget)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(latest.Version, upgraded[0].GetVersion())
+	})
+}
+
+func TestUpgradePath(t *testing.T) {
+	tests := []struct {
+		description string
+		from        string
+		to          string
+		expected    []string
+		shouldErr   string
+	}{
+		{
+			description: "consecutive versions",
+			from:        "skaffold/v1alpha1",
+			to:          "skaffold/v1alpha3",
+			expected:    []string{"skaffold/v1alpha1", "skaffold/v1alpha2", "skaffold/v1alpha3"},
+		},
+		{
+			description: "same version",
+			from:        latest.Version,
+			to:          latest.Version,
+			expected:    []string{latest.Version},
+		},
+		{
+			description: "target older than source",
+			from:        latest.Version,
+			to:          "skaffold/v1alpha1",
+			shouldErr:   "more recent than target",
+		},
+		{
+			description: "unknown source version",
+			from:        "skaffold/v1beta100",
+			to:          latest.Version,
+			shouldErr:   `unknown api version "skaffold/v1beta100"`,
+		},
+		{
+			description: "unknown target version",
+			from:        "skaffold/v1alpha1",
+			to:          "skaffold/v1beta100",
+			shouldErr:   `unknown api version "skaffold/v1beta100"`,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			path, err := UpgradePath(test.from, test.to)
+
+			if test.shouldErr != "" {
+				t.CheckErrorContains(test.shouldErr, err)
+				return
+			}
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expected, path)
+		})
+	}
+	testutil.Run(t, "full chain ends at the latest version", func(t *testutil.T) {
+		path, err := UpgradePath("skaffold/v1alpha1", latest.Version)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(SupportedVersions(), path)
+	})
+}
+
+func TestUpgradeWithDiff(t *testing.T) {
+	oldConfig := fmt.Sprintf("apiVersion: skaffold/v1beta6\nkind: Config\n%s", simpleConfig)
+
+	testutil.Run(t, "the diff shows the apiVersion change and is stable", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", oldConfig)
+		cfgs, err := ParseConfig(tmpDir.Path("skaffold.yaml"))
+		t.CheckNoError(err)
+
+		upgraded, diff, err := UpgradeWithDiff(cfgs[0])
+
+		t.CheckNoError(err)
+		t.CheckTrue(strings.Contains(diff, "--- skaffold/v1beta6"))
+		t.CheckTrue(strings.Contains(diff, "+++ "+upgraded.GetVersion()))
+		t.CheckTrue(strings.Contains(diff, "-apiVersion: skaffold/v1beta6"))
+		t.CheckTrue(strings.Contains(diff, "+apiVersion: "+upgraded.GetVersion()))
+
+		// a second run produces the identical diff.
+		cfgs, err = ParseConfig(tmpDir.Path("skaffold.yaml"))
+		t.CheckNoError(err)
+		_, again, err := UpgradeWithDiff(cfgs[0])
+		t.CheckNoError(err)
+		t.CheckDeepEqual(diff, again)
+	})
+}
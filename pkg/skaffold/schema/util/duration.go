@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// NormalizeDuration validates a duration-like config value and returns its
+// canonical form (e.g. `20m` becomes `20m0s`), so the schema's duration fields
+// are validated and rendered consistently. field names the config field for
+// the error message. Empty values are returned unchanged.
+func NormalizeDuration(field, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid duration %q for %s: %w", value, field, err)
+	}
+	return d.String(), nil
+}
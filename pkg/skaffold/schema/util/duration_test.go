@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestNormalizeDuration(t *testing.T) {
+	tests := []struct {
+		description string
+		value       string
+		expected    string
+		shouldErr   bool
+	}{
+		{description: "minutes gain the canonical seconds suffix", value: "20m", expected: "20m0s"},
+		{description: "seconds roll up into larger units", value: "3600s", expected: "1h0m0s"},
+		{description: "already canonical values are unchanged", value: "1m30s", expected: "1m30s"},
+		{description: "empty values stay empty", value: "", expected: ""},
+		{description: "non-durations are rejected", value: "soon", shouldErr: true},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			normalized, err := NormalizeDuration("cluster.timeout", test.value)
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expected, normalized)
+			if test.shouldErr {
+				t.CheckErrorContains("cluster.timeout", err)
+			}
+		})
+	}
+}
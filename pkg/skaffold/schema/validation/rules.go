@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+// RuleDescriptor describes one validation rule applied to a Skaffold config,
+// so tooling and docs can enumerate them and tests can assert coverage.
+type RuleDescriptor struct {
+	// ID uniquely names the rule.
+	ID string
+	// Description says what the rule checks, in one sentence.
+	Description string
+	// Severity is SeverityError for rules that fail the run and
+	// SeverityWarning for rules that only warn.
+	Severity string
+}
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// rules lists every validation rule Process and ProcessWithRunContext apply.
+// Keep it in sync when adding or removing a validate* function.
+var rules = []RuleDescriptor{
+	{ID: "tag-policy", Description: "only one tag policy may be set, and the sha256 policy cannot be combined with tryImportMissing", Severity: SeverityError},
+	{ID: "status-check-deadline", Description: "statusCheckDeadlineSeconds must be a number of seconds", Severity: SeverityError},
+	{ID: "status-check-hierarchy", Description: "per-deployer readiness timeouts must not exceed the global status check deadline", Severity: SeverityError},
+	{ID: "image-names", Description: "artifact image names must be unique, valid base names without tags or digests", Severity: SeverityError},
+	{ID: "artifact-dependencies", Description: "artifact dependencies must reference known artifacts, without cycles, with valid unique aliases", Severity: SeverityError},
+	{ID: "docker-network-mode", Description: "docker network mode must be one of none, bridge, host or container:<name>, and the named container must exist", Severity: SeverityError},
+	{ID: "custom-dependencies", Description: "custom artifacts cannot combine dependency paths with a dependency command", Severity: SeverityError},
+	{ID: "sync-rules", Description: "sync rule destinations must be valid for their source globs", Severity: SeverityError},
+	{ID: "port-forward-resources", Description: "port forward resources must name a valid resource type and port", Severity: SeverityError},
+	{ID: "jib-plugin-types", Description: "jib artifacts must use a supported plugin type", Severity: SeverityError},
+	{ID: "ko-sync", Description: "ko artifacts using infer sync must have a supported main path", Severity: SeverityError},
+	{ID: "log-prefix", Description: "log prefix must be one of auto, container, podAndContainer or none", Severity: SeverityError},
+	{ID: "artifact-types", Description: "artifact types must be supported by the configured builder", Severity: SeverityError},
+	{ID: "build-concurrency", Description: "build concurrency must be a non-negative integer", Severity: SeverityError},
+	{ID: "custom-test", Description: "custom tests cannot combine dependency paths with a dependency command", Severity: SeverityError},
+	{ID: "gcb-config", Description: "Google Cloud Build worker pool must match projects/*/locations/*/workerPools/*", Severity: SeverityError},
+	{ID: "verify-tests", Description: "verify test names must be unique and requested verify tests must exist", Severity: SeverityError},
+	{ID: "kpt-renderer-version", Description: "the kpt deployer requires a supported kpt version in the renderer", Severity: SeverityError},
+	{ID: "kube-context-consistency", Description: "configs resolved together must agree on the kube-context", Severity: SeverityError},
+	{ID: "kubectl-manifests", Description: "kubectl manifest globs must match at least one file", Severity: SeverityError},
+	{ID: "cloud-run-service-images", Description: "Cloud Run service manifests must reference a built artifact image", Severity: SeverityError},
+	{ID: "cloud-run-location", Description: "the Cloud Run deployer requires a project region to be set", Severity: SeverityError},
+	{ID: "cloud-run-deployer-mixing", Description: "the Cloud Run deployer should not be mixed with other deployers", Severity: SeverityWarning},
+	{ID: "deploy-image-registries", Description: "built artifacts should push to a registry the configured deployer can pull from", Severity: SeverityWarning},
+	{ID: "artifacts-deployed", Description: "built artifacts should be referenced by the deploy configuration", Severity: SeverityWarning},
+	{ID: "duplicate-dockerfiles", Description: "artifacts should not share the same Dockerfile in the same context", Severity: SeverityWarning},
+}
+
+// ValidationRules returns a descriptor for every validation rule applied to a
+// Skaffold config, for documentation and testing.
+func ValidationRules() []RuleDescriptor {
+	descriptors := make([]RuleDescriptor, len(rules))
+	copy(descriptors, rules)
+	return descriptors
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestValidationRules(t *testing.T) {
+	descriptors := ValidationRules()
+
+	byID := map[string]RuleDescriptor{}
+	for _, rule := range descriptors {
+		if _, present := byID[rule.ID]; present {
+			t.Errorf("duplicate rule id %q", rule.ID)
+		}
+		if rule.Description == "" {
+			t.Errorf("rule %q has no description", rule.ID)
+		}
+		if rule.Severity != SeverityError && rule.Severity != SeverityWarning {
+			t.Errorf("rule %q has unknown severity %q", rule.ID, rule.Severity)
+		}
+		byID[rule.ID] = rule
+	}
+
+	// the registry must cover the long-standing rules docs link to.
+	for _, id := range []string{"tag-policy", "status-check-deadline"} {
+		if _, present := byID[id]; !present {
+			t.Errorf("expected rule %q in the registry", id)
+		}
+	}
+
+	// callers may reorder or filter the returned slice without affecting the registry.
+	descriptors[0] = RuleDescriptor{}
+	testutil.CheckDeepEqual(t, false, ValidationRules()[0] == RuleDescriptor{})
+}
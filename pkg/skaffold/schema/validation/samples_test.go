@@ -84,7 +84,7 @@ func TestParseSamples(t *testing.T) {
 
 func checkSkaffoldConfig(t *testutil.T, yaml []byte) {
 	configFile := t.TempFile("skaffold.yaml", yaml)
-	parsed, err := schema.ParseConfigAndUpgrade(configFile)
+	parsed, _, err := schema.ParseConfigAndUpgrade(configFile)
 	t.CheckNoError(err)
 	var cfgs parser.SkaffoldConfigSet
 	for _, p := range parsed {
@@ -19,13 +19,17 @@ package validation
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/bmatcuk/doublestar"
 	"github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/misc"
@@ -41,6 +45,8 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util/stringslice"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yaml"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/yamltags"
 	"github.com/GoogleContainerTools/skaffold/proto/v1"
 )
@@ -84,16 +90,24 @@ func ProcessToErrorWithLocation(configs parser.SkaffoldConfigSet, validateConfig
 		errs = append(errs, validateKoSync(config, config.Build.Artifacts)...)
 		errs = append(errs, validateLogPrefix(config, config.Deploy.Logs)...)
 		errs = append(errs, validateArtifactTypes(config, config.Build)...)
+		errs = append(errs, validateBuildConcurrency(config, config.Build)...)
 		errs = append(errs, validateTaggingPolicy(config, config.Build)...)
 		errs = append(errs, validateCustomTest(config, config.Test)...)
 		errs = append(errs, validateGCBConfig(config, config.Build)...)
 		errs = append(errs, validateVerifyTests(config, config.Verify)...)
 		errs = append(errs, validateKptRendererVersion(config, config.Deploy, config.Render)...)
+		errs = append(errs, validateStatusCheckHierarchy(config, config.Deploy)...)
+		validateCloudRunDeployerMixing(config.Deploy)
+		validateDeployImageRegistries(config.Build, config.Deploy)
+		validateArtifactsAreDeployed(config.Build, config.Deploy)
+		validateDuplicateDockerfiles(config.Build.Artifacts)
 	}
 	errs = append(errs, validateArtifactDependencies(configs)...)
+	errs = append(errs, validateKubeContextConsistency(configs)...)
 	if validateConfig.CheckDeploySource {
 		// TODO(6050) validate for other deploy types - helm, kpt, etc.
 		errs = append(errs, validateKubectlManifests(configs)...)
+		errs = append(errs, validateCloudRunServiceImages(configs)...)
 	}
 	if len(errs) == 0 {
 		return nil
@@ -577,12 +591,30 @@ func validateSyncRules(cfg *parser.SkaffoldConfigEntry, artifacts []*latest.Arti
 						Location: cfg.YAMLInfos.LocateField(cfg.Build.Artifacts[i], "Sync"),
 					})
 				}
+				warnOnEmptySyncGlob(a, r.Src)
+			}
+			for _, src := range a.Sync.Infer {
+				warnOnEmptySyncGlob(a, src)
 			}
 		}
 	}
 	return cfgErrs
 }
 
+// warnOnEmptySyncGlob warns when a sync source glob matches no file in the artifact
+// workspace: the rule is almost certainly stale or mistyped, and changes the user
+// expects to sync will instead trigger full rebuilds.
+func warnOnEmptySyncGlob(a *latest.Artifact, src string) {
+	matches, err := doublestar.Glob(filepath.Join(a.Workspace, filepath.FromSlash(src)))
+	if err != nil {
+		// invalid patterns surface when the sync rules are evaluated.
+		return
+	}
+	if len(matches) == 0 {
+		warnings.Printf("sync rule pattern '%s' for image '%s' matches no file in the workspace", src, a.ImageName)
+	}
+}
+
 // validatePortForwardResources checks that all user defined port forward resources
 // have a valid resourceType
 func validatePortForwardResources(cfg *parser.SkaffoldConfigEntry, pfrs []*latest.PortForwardResource) []ErrorWithLocation {
@@ -655,41 +687,233 @@ func validateKoSync(cfg *parser.SkaffoldConfigEntry, artifacts []*latest.Artifac
 }
 
 // validateArtifactTypes checks that the artifact types are compatible with the specified builder.
-func validateArtifactTypes(cfg *parser.SkaffoldConfigEntry, bc latest.BuildConfig) []ErrorWithLocation {
-	cfgErrs := []ErrorWithLocation{}
+// builderCompatibility pairs a build type with the artifact types it supports
+// and the advice given when an artifact falls outside that set.
+type builderCompatibility struct {
+	// name of the builder as shown to the user.
+	name string
+	// allowed artifact types; nil means every type except those in disallowed.
+	allowed []string
+	// disallowed artifact types, used when the builder supports all but a few.
+	disallowed []string
+	// advice tells the user how to fix the pairing.
+	advice string
+}
+
+// artifactCompatibility returns the compatibility matrix entry for the
+// configured build type, or nil when any pairing is valid.
+func artifactCompatibility(bc latest.BuildConfig) *builderCompatibility {
 	switch {
 	case bc.LocalBuild != nil:
-		for i, a := range bc.Artifacts {
-			if misc.ArtifactType(a) == misc.Kaniko {
-				cfgErrs = append(cfgErrs, ErrorWithLocation{
-					Error:    fmt.Errorf("found a '%s' artifact, which is incompatible with the 'local' builder:\n\n%s\n\nTo use the '%s' builder, add the 'cluster' stanza to the 'build' section of your configuration. For information, see https://skaffold.dev/docs/pipeline-stages/builders/", misc.ArtifactType(a), misc.FormatArtifact(a), misc.ArtifactType(a)),
-					Location: cfg.YAMLInfos.Locate(&cfg.Build.Artifacts[i].ArtifactType),
-				})
-			}
+		return &builderCompatibility{
+			name:       "local",
+			disallowed: []string{misc.Kaniko},
+			advice:     "add the 'cluster' stanza to the 'build' section of your configuration",
 		}
 	case bc.GoogleCloudBuild != nil:
-		for i, a := range bc.Artifacts {
-			at := misc.ArtifactType(a)
-			if at != misc.Kaniko && at != misc.Docker && at != misc.Jib && at != misc.Buildpack && at != misc.Ko {
-				cfgErrs = append(cfgErrs, ErrorWithLocation{
-					Error:    fmt.Errorf("found a '%s' artifact, which is incompatible with the 'gcb' builder:\n\n%s\n\nTo use the '%s' builder, remove the 'googleCloudBuild' stanza from the 'build' section of your configuration. For information, see https://skaffold.dev/docs/pipeline-stages/builders/", misc.ArtifactType(a), misc.FormatArtifact(a), misc.ArtifactType(a)),
-					Location: cfg.YAMLInfos.Locate(&cfg.Build.Artifacts[i].ArtifactType),
-				})
-			}
+		return &builderCompatibility{
+			name:    "gcb",
+			allowed: []string{misc.Kaniko, misc.Docker, misc.Jib, misc.Buildpack, misc.Ko},
+			advice:  "remove the 'googleCloudBuild' stanza from the 'build' section of your configuration",
 		}
 	case bc.Cluster != nil:
-		for i, a := range bc.Artifacts {
-			if misc.ArtifactType(a) != misc.Kaniko && misc.ArtifactType(a) != misc.Custom {
-				cfgErrs = append(cfgErrs, ErrorWithLocation{
-					Error:    fmt.Errorf("found a '%s' artifact, which is incompatible with the 'cluster' builder:\n\n%s\n\nTo use the '%s' builder, remove the 'cluster' stanza from the 'build' section of your configuration. For information, see https://skaffold.dev/docs/pipeline-stages/builders/", misc.ArtifactType(a), misc.FormatArtifact(a), misc.ArtifactType(a)),
-					Location: cfg.YAMLInfos.Locate(&cfg.Build.Artifacts[i].ArtifactType),
-				})
-			}
+		return &builderCompatibility{
+			name:    "cluster",
+			allowed: []string{misc.Kaniko, misc.Custom},
+			advice:  "remove the 'cluster' stanza from the 'build' section of your configuration",
+		}
+	}
+	return nil
+}
+
+// compatible reports whether the artifact type is valid for this builder.
+func (b *builderCompatibility) compatible(artifactType string) bool {
+	if b.allowed != nil {
+		return stringslice.Contains(b.allowed, artifactType)
+	}
+	return !stringslice.Contains(b.disallowed, artifactType)
+}
+
+func validateArtifactTypes(cfg *parser.SkaffoldConfigEntry, bc latest.BuildConfig) []ErrorWithLocation {
+	cfgErrs := []ErrorWithLocation{}
+	builder := artifactCompatibility(bc)
+	if builder == nil {
+		return cfgErrs
+	}
+	for i, a := range bc.Artifacts {
+		if at := misc.ArtifactType(a); !builder.compatible(at) {
+			cfgErrs = append(cfgErrs, ErrorWithLocation{
+				Error:    fmt.Errorf("found a '%s' artifact, which is incompatible with the '%s' builder:\n\n%s\n\nTo use the '%s' builder, %s. For information, see https://skaffold.dev/docs/pipeline-stages/builders/", at, builder.name, misc.FormatArtifact(a), at, builder.advice),
+				Location: cfg.YAMLInfos.Locate(&cfg.Build.Artifacts[i].ArtifactType),
+			})
 		}
 	}
 	return cfgErrs
 }
 
+// validateCloudRunDeployerMixing warns when a Cloud Run deploy and a Kubernetes deploy are
+// configured in the same config document. The combination is allowed, but it is often a
+// mistake and usually better expressed as separate configs.
+func validateCloudRunDeployerMixing(dc latest.DeployConfig) {
+	if dc.CloudRunDeploy == nil {
+		return
+	}
+	var k8sDeployer string
+	switch {
+	case dc.KubectlDeploy != nil:
+		k8sDeployer = "kubectl"
+	case dc.LegacyHelmDeploy != nil:
+		k8sDeployer = "helm"
+	case dc.KptDeploy != nil:
+		k8sDeployer = "kpt"
+	default:
+		return
+	}
+	warnings.Printf("config defines both a Cloud Run deploy and a '%s' deploy: "+
+		"if this is not intentional, consider splitting them into separate configs", k8sDeployer)
+}
+
+// validateStatusCheckHierarchy checks that the deploy's status-check timeouts
+// form a coherent hierarchy: a per-deployer readiness timeout longer than the
+// global status check deadline can never complete, so the inversion is
+// rejected at parse time instead of timing out every deploy.
+func validateStatusCheckHierarchy(cfg *parser.SkaffoldConfigEntry, dc latest.DeployConfig) (cfgErrs []ErrorWithLocation) {
+	if dc.StatusCheckDeadlineSeconds <= 0 {
+		return
+	}
+	deadline := time.Duration(dc.StatusCheckDeadlineSeconds) * time.Second
+	if dc.CloudRunDeploy != nil && dc.CloudRunDeploy.ReadinessTimeout != "" {
+		// malformed durations are reported by the deployer; only compare
+		// values that parse.
+		if readiness, err := time.ParseDuration(dc.CloudRunDeploy.ReadinessTimeout); err == nil && readiness > deadline {
+			cfgErrs = append(cfgErrs, ErrorWithLocation{
+				Error:    fmt.Errorf("cloudrun.readinessTimeout (%s) exceeds statusCheckDeadlineSeconds (%d): the readiness wait could never finish; raise the deadline or lower the timeout", dc.CloudRunDeploy.ReadinessTimeout, dc.StatusCheckDeadlineSeconds),
+				Location: cfg.YAMLInfos.LocateField(cfg, "Deploy"),
+			})
+		}
+	}
+	return
+}
+
+// validateDeployImageRegistries warns when built artifacts target a registry the
+// configured deployer cannot pull from. Cloud Run only deploys images from
+// Container Registry or Artifact Registry, so a mismatched registry is clearer at
+// parse time than as a failed deploy. Image names without an explicit registry are
+// skipped: the default repo usually re-homes them.
+func validateDeployImageRegistries(bc latest.BuildConfig, dc latest.DeployConfig) {
+	if dc.CloudRunDeploy == nil {
+		return
+	}
+	for _, a := range bc.Artifacts {
+		ref, err := name.ParseReference(a.ImageName, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		registry := ref.Context().Registry.Name()
+		if registry == name.DefaultRegistry && !strings.HasPrefix(a.ImageName, "docker.io/") && !strings.HasPrefix(a.ImageName, name.DefaultRegistry+"/") {
+			// registry-less image name, not a DockerHub target.
+			continue
+		}
+		if registry == "gcr.io" || strings.HasSuffix(registry, ".gcr.io") || strings.HasSuffix(registry, "-docker.pkg.dev") {
+			continue
+		}
+		warnings.Printf("artifact '%s' pushes to registry '%s' but Cloud Run can only pull from Container Registry (*gcr.io) or Artifact Registry (*-docker.pkg.dev)", a.ImageName, registry)
+	}
+}
+
+// validateKubeContextConsistency checks that configs resolved together through `requires`
+// agree on the kube-context they deploy to. Imported configs silently redefining the
+// context of the root config is almost always a mistake; an explicit conflict error is
+// clearer than deploying half the configs to a different cluster.
+func validateKubeContextConsistency(configs parser.SkaffoldConfigSet) (errs []ErrorWithLocation) {
+	var firstContext string
+	var firstConfig *parser.SkaffoldConfigEntry
+	for _, c := range configs {
+		kubeContext := c.Deploy.KubeContext
+		if kubeContext == "" {
+			continue
+		}
+		if firstContext == "" {
+			firstContext = kubeContext
+			firstConfig = c
+			continue
+		}
+		if kubeContext != firstContext {
+			errs = append(errs, ErrorWithLocation{
+				Error: fmt.Errorf("config %q sets kube-context %q but config %q sets kube-context %q: configs resolved together must deploy to the same context, or override it explicitly with --kube-context",
+					firstConfig.Metadata.Name, firstContext, c.Metadata.Name, kubeContext),
+				Location: c.YAMLInfos.Locate(&c.Deploy),
+			})
+		}
+	}
+	return
+}
+
+// validateArtifactsAreDeployed warns when a config builds artifacts but configures no deployer:
+// the images get built (and possibly pushed) but are never deployed anywhere. This is valid for
+// build-only configs, so it is surfaced as a warning rather than an error.
+func validateArtifactsAreDeployed(bc latest.BuildConfig, dc latest.DeployConfig) {
+	if len(bc.Artifacts) == 0 {
+		return
+	}
+	if dc.DockerDeploy != nil || dc.LegacyHelmDeploy != nil || dc.KptDeploy != nil || dc.KubectlDeploy != nil || dc.CloudRunDeploy != nil {
+		return
+	}
+	warnings.Printf("config defines %d build artifact(s) but no deploy type: the built images will not be deployed", len(bc.Artifacts))
+}
+
+// validateDuplicateDockerfiles warns when two docker artifacts build the same
+// Dockerfile from the same workspace: the resulting images are identical, which
+// is almost always a copy-paste mistake. Sharing a workspace with different
+// Dockerfiles is fine.
+func validateDuplicateDockerfiles(artifacts []*latest.Artifact) {
+	seen := map[string]string{}
+	for _, a := range artifacts {
+		if a.DockerArtifact == nil {
+			continue
+		}
+		dockerfile := a.DockerArtifact.DockerfilePath
+		if dockerfile == "" {
+			dockerfile = constants.DefaultDockerfilePath
+		}
+		key := filepath.Join(a.Workspace, dockerfile)
+		if prev, found := seen[key]; found {
+			warnings.Printf("artifacts '%s' and '%s' build the same Dockerfile '%s' from workspace '%s': the resulting images will be identical", prev, a.ImageName, dockerfile, a.Workspace)
+			continue
+		}
+		seen[key] = a.ImageName
+	}
+}
+
+// validateBuildConcurrency checks that the build concurrency setting is consistent with the
+// configured build type: it must be a non-negative integer, and it is ignored when the build
+// defines at most one artifact.
+func validateBuildConcurrency(cfg *parser.SkaffoldConfigEntry, bc latest.BuildConfig) (cfgErrs []ErrorWithLocation) {
+	var concurrency *int
+	switch {
+	case bc.LocalBuild != nil:
+		concurrency = bc.LocalBuild.Concurrency
+	case bc.GoogleCloudBuild != nil:
+		concurrency = &bc.GoogleCloudBuild.Concurrency
+	case bc.Cluster != nil:
+		concurrency = &bc.Cluster.Concurrency
+	}
+	if concurrency == nil {
+		return
+	}
+	if *concurrency < 0 {
+		cfgErrs = append(cfgErrs, ErrorWithLocation{
+			Error:    fmt.Errorf("invalid build concurrency '%d': concurrency must be a non-negative integer, where 0 means no limit", *concurrency),
+			Location: cfg.YAMLInfos.Locate(&cfg.Build),
+		})
+		return
+	}
+	if *concurrency > 1 && len(bc.Artifacts) <= 1 {
+		warnings.Printf("build concurrency '%d' is ignored: only %d artifact is defined", *concurrency, len(bc.Artifacts))
+	}
+	return
+}
+
 // validateGCBConfig checks if GCB config is valid.
 func validateGCBConfig(cfg *parser.SkaffoldConfigEntry, bc latest.BuildConfig) (cfgErrs []ErrorWithLocation) {
 	if bc.GoogleCloudBuild != nil && bc.GoogleCloudBuild.WorkerPool != "" {
@@ -854,6 +1078,75 @@ func validateKubectlManifests(configs parser.SkaffoldConfigSet) (errs []ErrorWit
 	return errs
 }
 
+// cloudRunServiceManifest is the subset of a Cloud Run Service manifest needed to
+// check which images the service expects.
+type cloudRunServiceManifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image string `yaml:"image"`
+				} `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// validateCloudRunServiceImages checks that every image a Cloud Run Service
+// manifest references is produced by one of the config's build artifacts, so a
+// mistyped repository path fails at parse time instead of deploying a stale image.
+func validateCloudRunServiceImages(configs parser.SkaffoldConfigSet) (errs []ErrorWithLocation) {
+	for _, c := range configs {
+		if c.IsRemote || c.Deploy.CloudRunDeploy == nil || len(c.Build.Artifacts) == 0 {
+			continue
+		}
+		built := make(map[string]bool)
+		for _, a := range c.Build.Artifacts {
+			built[docker.SanitizeImageName(a.ImageName)] = true
+		}
+		for _, pattern := range c.Render.RawK8s {
+			if util.IsURL(pattern) {
+				continue
+			}
+			expanded, err := filepath.Glob(pattern)
+			if err != nil {
+				continue
+			}
+			for _, path := range expanded {
+				buf, err := os.ReadFile(path)
+				if err != nil {
+					continue
+				}
+				for _, doc := range strings.Split(string(buf), "\n---") {
+					var service cloudRunServiceManifest
+					if err := yaml.Unmarshal([]byte(doc), &service); err != nil {
+						continue
+					}
+					if service.APIVersion != "serving.knative.dev/v1" || service.Kind != "Service" {
+						continue
+					}
+					for _, container := range service.Spec.Template.Spec.Containers {
+						ref, err := docker.ParseReference(container.Image)
+						if err != nil || built[ref.BaseName] {
+							continue
+						}
+						errs = append(errs, ErrorWithLocation{
+							Error:    fmt.Errorf("Cloud Run service %q references image %q which is not produced by any build artifact", service.Metadata.Name, container.Image),
+							Location: c.YAMLInfos.Locate(&c.Build.Artifacts),
+						})
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
 func validateLocationSetForCloudRun(rCtx *runcontext.RunContext) []error {
 	if !requiresCloudRun(rCtx) {
 		// if the current command doesn't require connecting to Cloud Run, a location isn't needed.
@@ -907,3 +1200,84 @@ func requiresCloudRun(rCtx *runcontext.RunContext) bool {
 	_, ok := runCommands[rCtx.Opts.Command]
 	return ok
 }
+
+// skaffoldTemplateVariables are the variables Skaffold itself injects when
+// expanding templates, on top of the process environment. Suffixed variants
+// like IMAGE_REPO_<artifact> are matched by prefix.
+var (
+	skaffoldTemplateVariables        = []string{"IMAGE", "IMAGE_NAME", "IMAGE_REPO", "IMAGE_TAG", "IMAGE_DIGEST", "PUSH_IMAGE", "BUILD_CONTEXT", "PLATFORMS", "SKIP_TEST"}
+	skaffoldTemplateVariablePrefixes = []string{"IMAGE_NAME_", "IMAGE_REPO_", "IMAGE_TAG_", "IMAGE_DIGEST_"}
+
+	templateVariablePattern = regexp.MustCompile(`\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ValidateUndefinedTemplateVariables scans every string field of the given
+// configs for `{{.VAR}}` and `${VAR}` references and returns an error listing
+// the ones that resolve neither to a variable Skaffold provides nor to the
+// process environment.
+func ValidateUndefinedTemplateVariables(configs parser.SkaffoldConfigSet) error {
+	var undefined []string
+	for _, config := range configs {
+		refs := map[string]bool{}
+		collectTemplateVariables(reflect.ValueOf(config.SkaffoldConfig), refs)
+		for ref := range refs {
+			if !templateVariableDefined(ref) && !stringslice.Contains(undefined, ref) {
+				undefined = append(undefined, ref)
+			}
+		}
+	}
+	if len(undefined) == 0 {
+		return nil
+	}
+	sort.Strings(undefined)
+	return fmt.Errorf("config references undefined template variables: %v", undefined)
+}
+
+// collectTemplateVariables walks v recursively and records every template
+// variable referenced from a string field.
+func collectTemplateVariables(v reflect.Value, refs map[string]bool) {
+	switch v.Kind() {
+	case reflect.String:
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(v.String(), -1) {
+			for _, name := range match[1:] {
+				if name != "" {
+					refs[name] = true
+				}
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanInterface() {
+				collectTemplateVariables(v.Field(i), refs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectTemplateVariables(v.Index(i), refs)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			collectTemplateVariables(key, refs)
+			collectTemplateVariables(v.MapIndex(key), refs)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectTemplateVariables(v.Elem(), refs)
+		}
+	}
+}
+
+// templateVariableDefined returns true if name resolves to a Skaffold-provided
+// variable or to the process environment.
+func templateVariableDefined(name string) bool {
+	if stringslice.Contains(skaffoldTemplateVariables, name) {
+		return true
+	}
+	for _, prefix := range skaffoldTemplateVariablePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	_, found := os.LookupEnv(name)
+	return found
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/defaults"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/warnings"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
 
@@ -104,6 +105,68 @@ func TestValidateArtifactTypes(t *testing.T) {
 			},
 			expectedErrs: 1,
 		},
+		{
+			description: "local - bazel builder set",
+			bc: latest.BuildConfig{
+				BuildType: latest.BuildType{
+					LocalBuild: &latest.LocalBuild{},
+				},
+				Artifacts: []*latest.Artifact{
+					{
+						ImageName:    "leeroy-web",
+						Workspace:    "leeroy-web",
+						ArtifactType: latest.ArtifactType{BazelArtifact: &latest.BazelArtifact{}},
+					},
+				},
+			},
+		},
+		{
+			description: "local - kaniko builder set",
+			bc: latest.BuildConfig{
+				BuildType: latest.BuildType{
+					LocalBuild: &latest.LocalBuild{},
+				},
+				Artifacts: []*latest.Artifact{
+					{
+						ImageName:    "leeroy-web",
+						Workspace:    "leeroy-web",
+						ArtifactType: latest.ArtifactType{KanikoArtifact: &latest.KanikoArtifact{}},
+					},
+				},
+			},
+			expectedErrs: 1,
+		},
+		{
+			description: "cluster - custom builder set",
+			bc: latest.BuildConfig{
+				BuildType: latest.BuildType{
+					Cluster: &latest.ClusterDetails{},
+				},
+				Artifacts: []*latest.Artifact{
+					{
+						ImageName:    "leeroy-web",
+						Workspace:    "leeroy-web",
+						ArtifactType: latest.ArtifactType{CustomArtifact: &latest.CustomArtifact{}},
+					},
+				},
+			},
+		},
+		{
+			description: "cluster - buildpack builder set",
+			bc: latest.BuildConfig{
+				BuildType: latest.BuildType{
+					Cluster: &latest.ClusterDetails{},
+				},
+				Artifacts: []*latest.Artifact{
+					{
+						ImageName:    "leeroy-web",
+						Workspace:    "leeroy-web",
+						ArtifactType: latest.ArtifactType{BuildpackArtifact: &latest.BuildpackArtifact{}},
+					},
+				},
+			},
+			expectedErrs: 1,
+		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
@@ -1614,6 +1677,291 @@ func TestValidateTaggingPolicy(t *testing.T) {
 	}
 }
 
+func TestValidateCloudRunDeployerMixing(t *testing.T) {
+	tests := []struct {
+		description      string
+		deploy           latest.DeployConfig
+		expectedWarnings []string
+	}{
+		{
+			description: "cloud run only",
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{},
+				},
+			},
+		},
+		{
+			description: "kubectl only",
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					KubectlDeploy: &latest.KubectlDeploy{},
+				},
+			},
+		},
+		{
+			description: "cloud run mixed with kubectl",
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{},
+					KubectlDeploy:  &latest.KubectlDeploy{},
+				},
+			},
+			expectedWarnings: []string{"config defines both a Cloud Run deploy and a 'kubectl' deploy: " +
+				"if this is not intentional, consider splitting them into separate configs"},
+		},
+		{
+			description: "cloud run mixed with helm",
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy:   &latest.CloudRunDeploy{},
+					LegacyHelmDeploy: &latest.LegacyHelmDeploy{},
+				},
+			},
+			expectedWarnings: []string{"config defines both a Cloud Run deploy and a 'helm' deploy: " +
+				"if this is not intentional, consider splitting them into separate configs"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			err := Process(parser.SkaffoldConfigSet{
+				&parser.SkaffoldConfigEntry{
+					YAMLInfos: configlocations.NewYAMLInfos(),
+					SkaffoldConfig: &latest.SkaffoldConfig{
+						Pipeline: latest.Pipeline{
+							Deploy: test.deploy,
+						},
+					},
+				},
+			}, Options{CheckDeploySource: false})
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
+func TestValidateStatusCheckHierarchy(t *testing.T) {
+	tests := []struct {
+		description string
+		deploy      latest.DeployConfig
+		shouldErr   bool
+	}{
+		{
+			description: "readiness timeout within the global deadline",
+			deploy: latest.DeployConfig{
+				StatusCheckDeadlineSeconds: 60,
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{ReadinessTimeout: "30s"},
+				},
+			},
+		},
+		{
+			description: "readiness timeout exceeding the global deadline",
+			deploy: latest.DeployConfig{
+				StatusCheckDeadlineSeconds: 60,
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{ReadinessTimeout: "2m"},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			description: "no global deadline leaves the readiness timeout unchecked",
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{ReadinessTimeout: "2m"},
+				},
+			},
+		},
+		{
+			description: "malformed readiness timeouts are left to the deployer",
+			deploy: latest.DeployConfig{
+				StatusCheckDeadlineSeconds: 60,
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{ReadinessTimeout: "soon"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+
+			err := Process(parser.SkaffoldConfigSet{
+				&parser.SkaffoldConfigEntry{
+					YAMLInfos: configlocations.NewYAMLInfos(),
+					SkaffoldConfig: &latest.SkaffoldConfig{
+						Pipeline: latest.Pipeline{
+							Deploy: test.deploy,
+						},
+					},
+				}}, Options{CheckDeploySource: false})
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestValidateDeployImageRegistries(t *testing.T) {
+	tests := []struct {
+		description      string
+		artifacts        []*latest.Artifact
+		deploy           latest.DeployConfig
+		expectedWarnings []string
+	}{
+		{
+			description: "gcr image under cloud run",
+			artifacts:   []*latest.Artifact{{ImageName: "gcr.io/project/image"}},
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{},
+				},
+			},
+		},
+		{
+			description: "artifact registry image under cloud run",
+			artifacts:   []*latest.Artifact{{ImageName: "us-docker.pkg.dev/project/repo/image"}},
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{},
+				},
+			},
+		},
+		{
+			description: "registry-less image name is left to the default repo",
+			artifacts:   []*latest.Artifact{{ImageName: "image"}},
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{},
+				},
+			},
+		},
+		{
+			description: "dockerhub image under cloud run warns",
+			artifacts:   []*latest.Artifact{{ImageName: "docker.io/myuser/image"}},
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					CloudRunDeploy: &latest.CloudRunDeploy{},
+				},
+			},
+			expectedWarnings: []string{"artifact 'docker.io/myuser/image' pushes to registry 'index.docker.io' but Cloud Run can only pull from Container Registry (*gcr.io) or Artifact Registry (*-docker.pkg.dev)"},
+		},
+		{
+			description: "dockerhub image under kubectl does not warn",
+			artifacts:   []*latest.Artifact{{ImageName: "docker.io/myuser/image"}},
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					KubectlDeploy: &latest.KubectlDeploy{},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			validateDeployImageRegistries(latest.BuildConfig{Artifacts: test.artifacts}, test.deploy)
+
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
+func TestValidateBuildConcurrency(t *testing.T) {
+	tests := []struct {
+		description      string
+		cfg              latest.BuildConfig
+		shouldErr        bool
+		expectedWarnings []string
+	}{
+		{
+			description: "valid concurrency on local build",
+			cfg: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{
+					{ImageName: "image1"},
+					{ImageName: "image2"},
+				},
+				BuildType: latest.BuildType{
+					LocalBuild: &latest.LocalBuild{
+						Concurrency: util.Ptr(2),
+					},
+				},
+			},
+		},
+		{
+			description: "negative concurrency on local build",
+			shouldErr:   true,
+			cfg: latest.BuildConfig{
+				BuildType: latest.BuildType{
+					LocalBuild: &latest.LocalBuild{
+						Concurrency: util.Ptr(-1),
+					},
+				},
+			},
+		},
+		{
+			description: "negative concurrency on cluster build",
+			shouldErr:   true,
+			cfg: latest.BuildConfig{
+				BuildType: latest.BuildType{
+					Cluster: &latest.ClusterDetails{
+						Concurrency: -2,
+					},
+				},
+			},
+		},
+		{
+			description: "concurrency ignored with a single artifact",
+			cfg: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{
+					{ImageName: "image1"},
+				},
+				BuildType: latest.BuildType{
+					LocalBuild: &latest.LocalBuild{
+						Concurrency: util.Ptr(4),
+					},
+				},
+			},
+			expectedWarnings: []string{"build concurrency '4' is ignored: only 1 artifact is defined"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			err := Process(parser.SkaffoldConfigSet{
+				&parser.SkaffoldConfigEntry{
+					YAMLInfos: configlocations.NewYAMLInfos(),
+					SkaffoldConfig: &latest.SkaffoldConfig{
+						Pipeline: latest.Pipeline{
+							Build: test.cfg,
+							Deploy: latest.DeployConfig{
+								DeployType: latest.DeployType{
+									KubectlDeploy: &latest.KubectlDeploy{},
+								},
+							},
+						},
+					},
+				},
+			}, Options{CheckDeploySource: false})
+
+			t.CheckError(test.shouldErr, err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
 func TestValidateCustomTest(t *testing.T) {
 	tests := []struct {
 		description    string
@@ -1838,3 +2186,366 @@ func TestValidateCloudRunLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCloudRunServiceImages(t *testing.T) {
+	serviceManifest := `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - image: %s
+`
+	tests := []struct {
+		description  string
+		serviceImage string
+		shouldErr    bool
+	}{
+		{
+			description:  "service image is built by an artifact",
+			serviceImage: "gcr.io/p/app",
+		},
+		{
+			description:  "service references an image the build never produces",
+			serviceImage: "gcr.io/p/other",
+			shouldErr:    true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+			manifestPath := t.NewTempDir().
+				Write("service.yaml", fmt.Sprintf(serviceManifest, test.serviceImage)).
+				Path("service.yaml")
+
+			err := Process(parser.SkaffoldConfigSet{
+				&parser.SkaffoldConfigEntry{
+					YAMLInfos: configlocations.NewYAMLInfos(),
+					SkaffoldConfig: &latest.SkaffoldConfig{
+						Pipeline: latest.Pipeline{
+							Build: latest.BuildConfig{
+								Artifacts: []*latest.Artifact{{ImageName: "gcr.io/p/app"}},
+							},
+							Render: latest.RenderConfig{
+								Generate: latest.Generate{RawK8s: []string{manifestPath}},
+							},
+							Deploy: latest.DeployConfig{
+								DeployType: latest.DeployType{
+									CloudRunDeploy: &latest.CloudRunDeploy{},
+								},
+							},
+						},
+					},
+				}}, Options{CheckDeploySource: true})
+
+			t.CheckError(test.shouldErr, err)
+			if test.shouldErr {
+				t.CheckErrorContains("not produced by any build artifact", err)
+			}
+		})
+	}
+}
+
+func TestValidateArtifactsAreDeployed(t *testing.T) {
+	tests := []struct {
+		description      string
+		build            latest.BuildConfig
+		deploy           latest.DeployConfig
+		expectedWarnings []string
+	}{
+		{
+			description: "artifacts with a deploy type",
+			build: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{ImageName: "image1"}},
+			},
+			deploy: latest.DeployConfig{
+				DeployType: latest.DeployType{
+					KubectlDeploy: &latest.KubectlDeploy{},
+				},
+			},
+		},
+		{
+			description: "no artifacts and no deploy type",
+			build:       latest.BuildConfig{},
+			deploy:      latest.DeployConfig{},
+		},
+		{
+			description: "artifacts without a deploy type",
+			build: latest.BuildConfig{
+				Artifacts: []*latest.Artifact{{ImageName: "image1"}, {ImageName: "image2"}},
+			},
+			deploy:           latest.DeployConfig{},
+			expectedWarnings: []string{"config defines 2 build artifact(s) but no deploy type: the built images will not be deployed"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			err := Process(parser.SkaffoldConfigSet{
+				&parser.SkaffoldConfigEntry{
+					YAMLInfos: configlocations.NewYAMLInfos(),
+					SkaffoldConfig: &latest.SkaffoldConfig{
+						Pipeline: latest.Pipeline{
+							Build:  test.build,
+							Deploy: test.deploy,
+						},
+					},
+				},
+			}, Options{CheckDeploySource: false})
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
+func TestValidateKubeContextConsistency(t *testing.T) {
+	tests := []struct {
+		description  string
+		kubeContexts []string
+		shouldErr    bool
+	}{
+		{
+			description:  "no kube-contexts set",
+			kubeContexts: []string{"", ""},
+		},
+		{
+			description:  "all configs agree on the kube-context",
+			kubeContexts: []string{"prod", "prod"},
+		},
+		{
+			description:  "only one config sets a kube-context",
+			kubeContexts: []string{"", "prod"},
+		},
+		{
+			description:  "imported config declares a different kube-context",
+			kubeContexts: []string{"prod", "staging"},
+			shouldErr:    true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+
+			var set parser.SkaffoldConfigSet
+			for i, kubeContext := range test.kubeContexts {
+				set = append(set, &parser.SkaffoldConfigEntry{
+					YAMLInfos: configlocations.NewYAMLInfos(),
+					SkaffoldConfig: &latest.SkaffoldConfig{
+						Metadata: latest.Metadata{Name: fmt.Sprintf("cfg%d", i)},
+						Pipeline: latest.Pipeline{
+							Deploy: latest.DeployConfig{KubeContext: kubeContext},
+						},
+					},
+				})
+			}
+			err := Process(set, Options{CheckDeploySource: false})
+
+			if test.shouldErr {
+				t.CheckErrorContains("configs resolved together must deploy to the same context", err)
+			} else {
+				t.CheckNoError(err)
+			}
+		})
+	}
+}
+
+func TestValidateSyncRulesGlobMatches(t *testing.T) {
+	tests := []struct {
+		description      string
+		sync             *latest.Sync
+		expectedWarnings []string
+	}{
+		{
+			description: "manual rule matching a workspace file",
+			sync: &latest.Sync{Manual: []*latest.SyncRule{{
+				Src:  "*.js",
+				Dest: ".",
+			}}},
+		},
+		{
+			description: "manual rule matching nothing",
+			sync: &latest.Sync{Manual: []*latest.SyncRule{{
+				Src:  "src/**/*.py",
+				Dest: ".",
+			}}},
+			expectedWarnings: []string{"sync rule pattern 'src/**/*.py' for image 'img' matches no file in the workspace"},
+		},
+		{
+			description:      "infer rule matching nothing",
+			sync:             &latest.Sync{Infer: []string{"static/**"}},
+			expectedWarnings: []string{"sync rule pattern 'static/**' for image 'img' matches no file in the workspace"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			// disable yamltags validation
+			t.Override(&validateYamltags, func(interface{}) error { return nil })
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+			workspace := t.NewTempDir().Write("index.js", "").Root()
+
+			err := Process(parser.SkaffoldConfigSet{&parser.SkaffoldConfigEntry{
+				YAMLInfos: configlocations.NewYAMLInfos(),
+				SkaffoldConfig: &latest.SkaffoldConfig{
+					Pipeline: latest.Pipeline{
+						Build: latest.BuildConfig{
+							Artifacts: []*latest.Artifact{{
+								ImageName: "img",
+								Workspace: workspace,
+								Sync:      test.sync,
+							}},
+						},
+						Deploy: latest.DeployConfig{
+							DeployType: latest.DeployType{
+								KubectlDeploy: &latest.KubectlDeploy{},
+							},
+						},
+					},
+				}}}, Options{CheckDeploySource: false})
+
+			t.CheckNoError(err)
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
+
+func TestValidateUndefinedTemplateVariables(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         *latest.SkaffoldConfig
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			description: "skaffold-provided variables resolve",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						TagPolicy: latest.TagPolicy{
+							EnvTemplateTagger: &latest.EnvTemplateTagger{Template: "{{.IMAGE_NAME}}-suffix"},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "environment variables resolve",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Deploy: latest.DeployConfig{
+						KubeContext: "${TEST_CLUSTER_CONTEXT}",
+					},
+				},
+			},
+			env: map[string]string{"TEST_CLUSTER_CONTEXT": "minikube"},
+		},
+		{
+			description: "undefined variables are listed",
+			cfg: &latest.SkaffoldConfig{
+				Pipeline: latest.Pipeline{
+					Build: latest.BuildConfig{
+						TagPolicy: latest.TagPolicy{
+							EnvTemplateTagger: &latest.EnvTemplateTagger{Template: "{{.NOT_DEFINED_ANYWHERE}}"},
+						},
+					},
+					Deploy: latest.DeployConfig{
+						KubeContext: "${ALSO_NOT_DEFINED}",
+					},
+				},
+			},
+			expectedErr: "undefined template variables: [ALSO_NOT_DEFINED NOT_DEFINED_ANYWHERE]",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			err := ValidateUndefinedTemplateVariables(parser.SkaffoldConfigSet{
+				{SkaffoldConfig: test.cfg, YAMLInfos: configlocations.NewYAMLInfos()},
+			})
+
+			if test.expectedErr == "" {
+				t.CheckNoError(err)
+			} else {
+				t.CheckErrorContains(test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDuplicateDockerfiles(t *testing.T) {
+	tests := []struct {
+		description      string
+		artifacts        []*latest.Artifact
+		expectedWarnings []string
+	}{
+		{
+			description: "two identical docker artifacts warn",
+			artifacts: []*latest.Artifact{
+				{
+					ImageName:    "image-a",
+					Workspace:    "app",
+					ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"}},
+				},
+				{
+					ImageName:    "image-b",
+					Workspace:    "app",
+					ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"}},
+				},
+			},
+			expectedWarnings: []string{"artifacts 'image-a' and 'image-b' build the same Dockerfile 'Dockerfile' from workspace 'app': the resulting images will be identical"},
+		},
+		{
+			description: "same workspace with different Dockerfiles is fine",
+			artifacts: []*latest.Artifact{
+				{
+					ImageName:    "image-a",
+					Workspace:    "app",
+					ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"}},
+				},
+				{
+					ImageName:    "image-b",
+					Workspace:    "app",
+					ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile.dev"}},
+				},
+			},
+		},
+		{
+			description: "empty dockerfile path matches the default",
+			artifacts: []*latest.Artifact{
+				{
+					ImageName:    "image-a",
+					Workspace:    "app",
+					ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{}},
+				},
+				{
+					ImageName:    "image-b",
+					Workspace:    "app",
+					ArtifactType: latest.ArtifactType{DockerArtifact: &latest.DockerArtifact{DockerfilePath: "Dockerfile"}},
+				},
+			},
+			expectedWarnings: []string{"artifacts 'image-a' and 'image-b' build the same Dockerfile 'Dockerfile' from workspace 'app': the resulting images will be identical"},
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			fakeWarner := &warnings.Collect{}
+			t.Override(&warnings.Printf, fakeWarner.Warnf)
+
+			validateDuplicateDockerfiles(test.artifacts)
+
+			t.CheckDeepEqual(test.expectedWarnings, fakeWarner.Warnings)
+		})
+	}
+}
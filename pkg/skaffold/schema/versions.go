@@ -22,8 +22,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -106,75 +108,81 @@ type APIVersion struct {
 // SchemaVersionsV1 refers to all the supported API Schemas for skaffold v1 executables.e.g. skaffold 1.13. The API
 // schema versions are in the range of v1alpha*, v1beta*, v2alpha* and v2beta*.
 var SchemaVersionsV1 = Versions{
-	{v1alpha1.Version, v1alpha1.NewSkaffoldConfig},
-	{v1alpha2.Version, v1alpha2.NewSkaffoldConfig},
-	{v1alpha3.Version, v1alpha3.NewSkaffoldConfig},
-	{v1alpha4.Version, v1alpha4.NewSkaffoldConfig},
-	{v1alpha5.Version, v1alpha5.NewSkaffoldConfig},
-	{v1beta1.Version, v1beta1.NewSkaffoldConfig},
-	{v1beta2.Version, v1beta2.NewSkaffoldConfig},
-	{v1beta3.Version, v1beta3.NewSkaffoldConfig},
-	{v1beta4.Version, v1beta4.NewSkaffoldConfig},
-	{v1beta5.Version, v1beta5.NewSkaffoldConfig},
-	{v1beta6.Version, v1beta6.NewSkaffoldConfig},
-	{v1beta7.Version, v1beta7.NewSkaffoldConfig},
-	{v1beta8.Version, v1beta8.NewSkaffoldConfig},
-	{v1beta9.Version, v1beta9.NewSkaffoldConfig},
-	{v1beta10.Version, v1beta10.NewSkaffoldConfig},
-	{v1beta11.Version, v1beta11.NewSkaffoldConfig},
-	{v1beta12.Version, v1beta12.NewSkaffoldConfig},
-	{v1beta13.Version, v1beta13.NewSkaffoldConfig},
-	{v1beta14.Version, v1beta14.NewSkaffoldConfig},
-	{v1beta15.Version, v1beta15.NewSkaffoldConfig},
-	{v1beta16.Version, v1beta16.NewSkaffoldConfig},
-	{v1beta17.Version, v1beta17.NewSkaffoldConfig},
-	{v1.Version, v1.NewSkaffoldConfig},
-	{v2alpha1.Version, v2alpha1.NewSkaffoldConfig},
-	{v2alpha2.Version, v2alpha2.NewSkaffoldConfig},
-	{v2alpha3.Version, v2alpha3.NewSkaffoldConfig},
-	{v2alpha4.Version, v2alpha4.NewSkaffoldConfig},
-	{v2beta1.Version, v2beta1.NewSkaffoldConfig},
-	{v2beta2.Version, v2beta2.NewSkaffoldConfig},
-	{v2beta3.Version, v2beta3.NewSkaffoldConfig},
-	{v2beta4.Version, v2beta4.NewSkaffoldConfig},
-	{v2beta5.Version, v2beta5.NewSkaffoldConfig},
-	{v2beta6.Version, v2beta6.NewSkaffoldConfig},
-	{v2beta7.Version, v2beta7.NewSkaffoldConfig},
-	{v2beta8.Version, v2beta8.NewSkaffoldConfig},
-	{v2beta9.Version, v2beta9.NewSkaffoldConfig},
-	{v2beta10.Version, v2beta10.NewSkaffoldConfig},
-	{v2beta11.Version, v2beta11.NewSkaffoldConfig},
-	{v2beta12.Version, v2beta12.NewSkaffoldConfig},
-	{v2beta13.Version, v2beta13.NewSkaffoldConfig},
-	{v2beta14.Version, v2beta14.NewSkaffoldConfig},
-	{v2beta15.Version, v2beta15.NewSkaffoldConfig},
-	{v2beta16.Version, v2beta16.NewSkaffoldConfig},
-	{v2beta17.Version, v2beta17.NewSkaffoldConfig},
-	{v2beta18.Version, v2beta18.NewSkaffoldConfig},
-	{v2beta19.Version, v2beta19.NewSkaffoldConfig},
-	{v2beta20.Version, v2beta20.NewSkaffoldConfig},
-	{v2beta21.Version, v2beta21.NewSkaffoldConfig},
-	{v2beta22.Version, v2beta22.NewSkaffoldConfig},
-	{v2beta23.Version, v2beta23.NewSkaffoldConfig},
-	{v2beta24.Version, v2beta24.NewSkaffoldConfig},
-	{v2beta25.Version, v2beta25.NewSkaffoldConfig},
-	{v2beta26.Version, v2beta26.NewSkaffoldConfig},
-	{v2beta27.Version, v2beta27.NewSkaffoldConfig},
-	{v2beta28.Version, v2beta28.NewSkaffoldConfig},
-	{latestV1.Version, latestV1.NewSkaffoldConfig},
-	{v3alpha1.Version, v3alpha1.NewSkaffoldConfig},
-	{v3.Version, v3.NewSkaffoldConfig},
+	{APIVersion: v1alpha1.Version, Factory: v1alpha1.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1alpha2.Version, Factory: v1alpha2.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1alpha3.Version, Factory: v1alpha3.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1alpha4.Version, Factory: v1alpha4.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1alpha5.Version, Factory: v1alpha5.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta1.Version, Factory: v1beta1.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta2.Version, Factory: v1beta2.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta3.Version, Factory: v1beta3.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta4.Version, Factory: v1beta4.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta5.Version, Factory: v1beta5.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta6.Version, Factory: v1beta6.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta7.Version, Factory: v1beta7.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta8.Version, Factory: v1beta8.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta9.Version, Factory: v1beta9.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta10.Version, Factory: v1beta10.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta11.Version, Factory: v1beta11.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta12.Version, Factory: v1beta12.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta13.Version, Factory: v1beta13.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta14.Version, Factory: v1beta14.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta15.Version, Factory: v1beta15.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta16.Version, Factory: v1beta16.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1beta17.Version, Factory: v1beta17.NewSkaffoldConfig, Deprecated: true},
+	{APIVersion: v1.Version, Factory: v1.NewSkaffoldConfig},
+	{APIVersion: v2alpha1.Version, Factory: v2alpha1.NewSkaffoldConfig},
+	{APIVersion: v2alpha2.Version, Factory: v2alpha2.NewSkaffoldConfig},
+	{APIVersion: v2alpha3.Version, Factory: v2alpha3.NewSkaffoldConfig},
+	{APIVersion: v2alpha4.Version, Factory: v2alpha4.NewSkaffoldConfig},
+	{APIVersion: v2beta1.Version, Factory: v2beta1.NewSkaffoldConfig},
+	{APIVersion: v2beta2.Version, Factory: v2beta2.NewSkaffoldConfig},
+	{APIVersion: v2beta3.Version, Factory: v2beta3.NewSkaffoldConfig},
+	{APIVersion: v2beta4.Version, Factory: v2beta4.NewSkaffoldConfig},
+	{APIVersion: v2beta5.Version, Factory: v2beta5.NewSkaffoldConfig},
+	{APIVersion: v2beta6.Version, Factory: v2beta6.NewSkaffoldConfig},
+	{APIVersion: v2beta7.Version, Factory: v2beta7.NewSkaffoldConfig},
+	{APIVersion: v2beta8.Version, Factory: v2beta8.NewSkaffoldConfig},
+	{APIVersion: v2beta9.Version, Factory: v2beta9.NewSkaffoldConfig},
+	{APIVersion: v2beta10.Version, Factory: v2beta10.NewSkaffoldConfig},
+	{APIVersion: v2beta11.Version, Factory: v2beta11.NewSkaffoldConfig},
+	{APIVersion: v2beta12.Version, Factory: v2beta12.NewSkaffoldConfig},
+	{APIVersion: v2beta13.Version, Factory: v2beta13.NewSkaffoldConfig},
+	{APIVersion: v2beta14.Version, Factory: v2beta14.NewSkaffoldConfig},
+	{APIVersion: v2beta15.Version, Factory: v2beta15.NewSkaffoldConfig},
+	{APIVersion: v2beta16.Version, Factory: v2beta16.NewSkaffoldConfig},
+	{APIVersion: v2beta17.Version, Factory: v2beta17.NewSkaffoldConfig},
+	{APIVersion: v2beta18.Version, Factory: v2beta18.NewSkaffoldConfig},
+	{APIVersion: v2beta19.Version, Factory: v2beta19.NewSkaffoldConfig},
+	{APIVersion: v2beta20.Version, Factory: v2beta20.NewSkaffoldConfig},
+	{APIVersion: v2beta21.Version, Factory: v2beta21.NewSkaffoldConfig},
+	{APIVersion: v2beta22.Version, Factory: v2beta22.NewSkaffoldConfig},
+	{APIVersion: v2beta23.Version, Factory: v2beta23.NewSkaffoldConfig},
+	{APIVersion: v2beta24.Version, Factory: v2beta24.NewSkaffoldConfig},
+	{APIVersion: v2beta25.Version, Factory: v2beta25.NewSkaffoldConfig},
+	{APIVersion: v2beta26.Version, Factory: v2beta26.NewSkaffoldConfig},
+	{APIVersion: v2beta27.Version, Factory: v2beta27.NewSkaffoldConfig},
+	{APIVersion: v2beta28.Version, Factory: v2beta28.NewSkaffoldConfig},
+	{APIVersion: latestV1.Version, Factory: latestV1.NewSkaffoldConfig},
+	{APIVersion: v3alpha1.Version, Factory: v3alpha1.NewSkaffoldConfig},
+	{APIVersion: v3.Version, Factory: v3.NewSkaffoldConfig},
 }
 
 // SchemaVersionsV2 refers to all the supported API Schemas for skaffold v2 executables. The API schema versions are
 // in the range of v3alpha*.
 var SchemaVersionsV2 = Versions{
-	{latest.Version, latest.NewSkaffoldConfig},
+	{APIVersion: latest.Version, Factory: latest.NewSkaffoldConfig},
 }
 
 type Version struct {
 	APIVersion string
 	Factory    func() util.VersionedConfig
+	// Deprecated marks a schema version teams should migrate away from.
+	// Parsing a deprecated version keeps working; it only produces a warning.
+	Deprecated bool
+	// RemovedIn optionally names the skaffold release that will drop the
+	// version, for the warning text.
+	RemovedIn string
 }
 
 type Versions []Version
@@ -190,20 +198,230 @@ func (v *Versions) Find(apiVersion string) (func() util.VersionedConfig, bool) {
 	return nil, false
 }
 
+// SupportedVersions returns every known apiVersion in order, oldest first.
+func SupportedVersions() []string {
+	versions := make([]string, 0, len(AllVersions))
+	for _, version := range AllVersions {
+		versions = append(versions, version.APIVersion)
+	}
+	return versions
+}
+
+// LatestVersion returns the newest supported apiVersion.
+func LatestVersion() string {
+	return AllVersions[len(AllVersions)-1].APIVersion
+}
+
+// AssertSupported checks that apiVersion can be handled by this Skaffold
+// release, erroring when the config declares a version more recent than the
+// latest one the binary supports.
+func AssertSupported(apiVersion string, skaffoldVersion string) error {
+	configVersion, err := apiversion.Parse(apiVersion)
+	if err != nil {
+		return err
+	}
+	latestVersion, err := apiversion.Parse(LatestVersion())
+	if err != nil {
+		return err
+	}
+	if configVersion.GT(latestVersion) {
+		return fmt.Errorf("config version %q is more recent than the latest version %q supported by Skaffold %s: upgrade Skaffold", apiVersion, LatestVersion(), skaffoldVersion)
+	}
+	return nil
+}
+
+// AssertUpgradeIdempotent checks that running cfg through Upgrade leaves the
+// source config itself untouched: an upgrade must build the next version's
+// config rather than mutate its receiver, so any difference points at an
+// accidental mutation in the upgrade path. A config already at the latest
+// version must instead return the documented "no version to upgrade from"
+// error.
+func AssertUpgradeIdempotent(cfg util.VersionedConfig) error {
+	before, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshaling config at version %q: %w", cfg.GetVersion(), err)
+	}
+	upgraded, err := cfg.Upgrade()
+	if cfg.GetVersion() == LatestVersion() {
+		if err == nil {
+			return fmt.Errorf("config at latest version %q must refuse to upgrade", cfg.GetVersion())
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("upgrading config from version %q: %w", cfg.GetVersion(), err)
+	}
+	if upgraded == nil || upgraded.GetVersion() == cfg.GetVersion() {
+		return fmt.Errorf("upgrading config from version %q did not advance the version", cfg.GetVersion())
+	}
+	after, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshaling config at version %q: %w", cfg.GetVersion(), err)
+	}
+	if !bytes.Equal(before, after) {
+		return fmt.Errorf("config at version %q was mutated by its upgrade to %q", cfg.GetVersion(), upgraded.GetVersion())
+	}
+	return nil
+}
+
 // IsSkaffoldConfig is for determining if a file is skaffold config file.
 func IsSkaffoldConfig(file string) bool {
-	if config, err := ParseConfig(file); err == nil && config != nil {
+	contents, err := misc.ReadConfiguration(file)
+	if err != nil {
+		return false
+	}
+	return IsSkaffoldConfigBytes(contents)
+}
+
+// IsSkaffoldConfigBytes is for determining if in-memory contents hold a
+// skaffold config, for callers such as editor integrations that have no file
+// on disk.
+func IsSkaffoldConfigBytes(contents []byte) bool {
+	if config, err := parseConfigBuffer(contents); err == nil && config != nil {
 		return true
 	}
 	return false
 }
 
+// DetectVersion reports the apiVersion of the first document of the config at
+// path without a full parse: only the apiVersion and kind fields are decoded,
+// so invalid fields elsewhere in the document are tolerated. Meant for quick
+// routing decisions, such as whether to run an upgrade, before the expensive
+// parse-and-validate. Files that are not skaffold configs at all are errors.
+func DetectVersion(path string) (string, error) {
+	contents, err := misc.ReadConfiguration(path)
+	if err != nil {
+		return "", fmt.Errorf("read skaffold config: %w", err)
+	}
+	var header struct {
+		Version string `yaml:"apiVersion"`
+		Kind    string `yaml:"kind"`
+	}
+	if err := yaml.NewDecoder(bytes.NewReader(contents)).Decode(&header); err != nil {
+		return "", fmt.Errorf("parsing %q: %w", path, err)
+	}
+	if header.Version == "" || header.Kind != "Config" {
+		return "", fmt.Errorf("%q is not a skaffold config", path)
+	}
+	return header.Version, nil
+}
+
 // ParseConfig reads a configuration file.
 func ParseConfig(filename string) ([]util.VersionedConfig, error) {
 	buf, err := misc.ReadConfiguration(filename)
 	if err != nil {
 		return nil, fmt.Errorf("read skaffold config: %w", err)
 	}
+	return parseConfigBuffer(buf)
+}
+
+// ParseConfigByName reads a multi-document configuration file and returns
+// only the document whose metadata.name matches name. Unknown names report
+// the names the file does define; a name shared by several documents is an
+// error, since the caller asked for exactly one config.
+func ParseConfigByName(path, name string) (util.VersionedConfig, error) {
+	buf, err := misc.ReadConfiguration(path)
+	if err != nil {
+		return nil, fmt.Errorf("read skaffold config: %w", err)
+	}
+	cfgs, err := parseConfigBuffer(buf)
+	if err != nil {
+		return nil, err
+	}
+	// the schema structs differ per version, so the names are decoded from
+	// the raw documents, index-aligned with the parsed configs.
+	decoder := yaml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf"))))
+	var names []string
+	for {
+		var header struct {
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		err := decoder.Decode(&header)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing config names: %w", err)
+		}
+		names = append(names, header.Metadata.Name)
+	}
+	matched := -1
+	for i, n := range names {
+		if n != name || i >= len(cfgs) {
+			continue
+		}
+		if matched >= 0 {
+			return nil, fmt.Errorf("multiple configs named %q in %s", name, path)
+		}
+		matched = i
+	}
+	if matched < 0 {
+		var available []string
+		for _, n := range names {
+			if n != "" {
+				available = append(available, n)
+			}
+		}
+		return nil, fmt.Errorf("no config named %q in %s: available names are %v", name, path, available)
+	}
+	return cfgs[matched], nil
+}
+
+// ParseConfigFromReader reads a configuration from an io.Reader, for callers
+// that hold the config in memory or pipe it through stdin rather than a file.
+// Multi-document streams are handled exactly as in ParseConfig.
+func ParseConfigFromReader(r io.Reader) ([]util.VersionedConfig, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read skaffold config: %w", err)
+	}
+	return parseConfigBuffer(buf)
+}
+
+// bounds applied when fetching a config over HTTP, so a misbehaving server
+// can neither stall the caller nor stream an unbounded body. Overridable for
+// testing.
+var (
+	remoteConfigTimeout       = 30 * time.Second
+	maxRemoteConfigSize int64 = 4 * 1024 * 1024
+)
+
+// ParseConfigFromURL fetches a configuration over HTTP(S) and parses it like
+// ParseConfigFromReader, for setups keeping a shared base config on an
+// artifact server. Redirects are followed; the download is bounded by
+// remoteConfigTimeout and maxRemoteConfigSize. Network failures name the URL
+// and the transport problem, while parse failures surface untouched, so the
+// two are distinguishable.
+func ParseConfigFromURL(ctx context.Context, url string) ([]util.VersionedConfig, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URL %q: %w", url, err)
+	}
+	client := &http.Client{Timeout: remoteConfigTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching skaffold config from %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching skaffold config from %q: status %s", url, resp.Status)
+	}
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching skaffold config from %q: %w", url, err)
+	}
+	if int64(len(buf)) > maxRemoteConfigSize {
+		return nil, fmt.Errorf("fetching skaffold config from %q: response exceeds %d bytes", url, maxRemoteConfigSize)
+	}
+	return parseConfigBuffer(buf)
+}
+
+func parseConfigBuffer(buf []byte) ([]util.VersionedConfig, error) {
+	// Windows editors sometimes save the file with a UTF-8 byte-order mark,
+	// which would make the first document's apiVersion unparseable.
+	buf = bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf"))
 	factories, err := configFactoryFromAPIVersion(buf)
 	if err != nil {
 		return nil, err
@@ -215,14 +433,48 @@ func ParseConfig(filename string) ([]util.VersionedConfig, error) {
 	return parseConfig(buf, factories)
 }
 
-// ParseConfigAndUpgrade reads a configuration file and upgrades it to a given version.
-func ParseConfigAndUpgrade(filename string) ([]util.VersionedConfig, error) {
+// ParseConfigAndUpgrade reads a configuration file and upgrades it to a given
+// version. The string slice carries one deprecation warning per source
+// version flagged deprecated; deprecated configs stay valid and usable, the
+// warnings are purely informational.
+func ParseConfigAndUpgrade(filename string) ([]util.VersionedConfig, []string, error) {
 	configs, err := ParseConfig(filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	deprecations := DeprecationWarnings(configs)
+	upgraded, err := UpgradeTo(configs, latest.Version)
+	if err != nil {
+		return nil, nil, err
 	}
+	return upgraded, deprecations, nil
+}
 
-	return UpgradeTo(configs, latest.Version)
+// DeprecationWarnings returns one warning per distinct source apiVersion
+// flagged deprecated in SchemaVersions, as a nudge to migrate before the
+// version is dropped.
+func DeprecationWarnings(configs []util.VersionedConfig) []string {
+	var deprecations []string
+	seen := map[string]bool{}
+	for _, cfg := range configs {
+		apiVersion := cfg.GetVersion()
+		if seen[apiVersion] {
+			continue
+		}
+		seen[apiVersion] = true
+		for _, version := range AllVersions {
+			if version.APIVersion != apiVersion || !version.Deprecated {
+				continue
+			}
+			warning := fmt.Sprintf("apiVersion %q is deprecated: run `skaffold fix` to upgrade to %q", apiVersion, latest.Version)
+			if version.RemovedIn != "" {
+				warning += fmt.Sprintf(" before support is removed in %s", version.RemovedIn)
+			}
+			deprecations = append(deprecations, warning)
+			break
+		}
+	}
+	return deprecations
 }
 
 // configFactoryFromAPIVersion checks that all configs in the input stream have the same API version, and returns a function to create a config with that API version.
@@ -236,14 +488,14 @@ func configFactoryFromAPIVersion(buf []byte) ([]func() util.VersionedConfig, err
 	var factories []func() util.VersionedConfig
 	b := bytes.NewReader(buf)
 	decoder := yaml.NewDecoder(b)
-	for {
+	for index := 1; ; index++ {
 		var v APIVersion
 		err := decoder.Decode(&v)
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("parsing api version: %w", err)
+			return nil, fmt.Errorf("parsing api version of document %d: %w", index, err)
 		}
 		factory, present := AllVersions.Find(v.Version)
 		if !present {
@@ -254,7 +506,10 @@ func configFactoryFromAPIVersion(buf []byte) ([]func() util.VersionedConfig, err
 	return factories, nil
 }
 
-// removeYamlAnchors removes all top-level keys starting with `.` from the input stream so they can be used as YAML anchors
+// removeYamlAnchors removes all top-level keys starting with `.` from the input stream so they can be used as YAML anchors.
+// The decode/encode round-trip also resolves aliases (`*base`) and merge keys
+// (`<<: *base`) into plain mappings, so the schema decoding afterwards never
+// sees them and repeated blocks can be DRYed up with anchors.
 func removeYamlAnchors(buf []byte) ([]byte, error) {
 	in := bytes.NewReader(buf)
 	var out bytes.Buffer
@@ -291,6 +546,9 @@ func removeYamlAnchors(buf []byte) ([]byte, error) {
 func parseConfig(buf []byte, factories []func() util.VersionedConfig) ([]util.VersionedConfig, error) {
 	b := bytes.NewReader(buf)
 	decoder := yaml.NewDecoder(b)
+	// fields the source schema does not know are rejected here rather than
+	// silently dropped, so an upgrade can never lose custom keys: configs
+	// containing them never parse in the first place.
 	decoder.KnownFields(true)
 	var cfgs []util.VersionedConfig
 	for index := 0; index < len(factories); index++ {
@@ -300,30 +558,69 @@ func parseConfig(buf []byte, factories []func() util.VersionedConfig) ([]util.Ve
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse config: %w", err)
+			// the yaml error already names the offending line; prefix it with
+			// which document of the stream it belongs to.
+			return nil, fmt.Errorf("unable to parse document %d of config: %w", index+1, err)
 		}
 		cfgs = append(cfgs, cfg)
 	}
 	return cfgs, nil
 }
 
+// CompatibilityError reports every config version that prevents the checked
+// configs from being handled together, so a repo mixing schema tracks learns
+// about all offending configs in one pass rather than one at a time.
+type CompatibilityError struct {
+	// Violations maps a schema track, such as "v1", "v2" or "unknown", to the
+	// offending apiVersions found for it.
+	Violations map[string][]string
+	message    string
+}
+
+func (e *CompatibilityError) Error() string {
+	return e.message
+}
+
+// versionTrack names the schema track an apiVersion belongs to, for grouping
+// compatibility violations.
+func versionTrack(apiVersion string) string {
+	switch {
+	case V1Pattern.MatchString(apiVersion):
+		return "v1"
+	case V2Pattern.MatchString(apiVersion):
+		return "v2"
+	default:
+		return "unknown"
+	}
+}
+
 // getLatestFromCompatibilityCheck makes sure the schema versions in SchemaVersionsV1 and SchemaVersionsV2 are not used
 // together and returns the latest version where this VersionedConfig slice belongs to.
 func getLatestFromCompatibilityCheck(cfgs []util.VersionedConfig) (string, error) {
-	var v1Track, v2Track []string
+	var v1Track, v2Track, unknownTrack []string
 	for _, cfg := range cfgs {
 		curVersion := cfg.GetVersion()
-		if matched := V1Pattern.MatchString(curVersion); matched {
+		switch versionTrack(curVersion) {
+		case "v1":
 			v1Track = append(v1Track, curVersion)
-		} else if matched := V2Pattern.MatchString(curVersion); matched {
+		case "v2":
 			v2Track = append(v2Track, curVersion)
-		} else {
-			return "", fmt.Errorf("unknown apiVersion %v", curVersion)
+		default:
+			unknownTrack = append(unknownTrack, curVersion)
 		}
 	}
 
+	if len(unknownTrack) > 0 {
+		return "", &CompatibilityError{
+			Violations: map[string][]string{"unknown": unknownTrack},
+			message:    fmt.Sprintf("unknown apiVersion %v", strings.Join(unknownTrack, ", ")),
+		}
+	}
 	if len(v1Track) > 0 && len(v2Track) > 0 {
-		return "", fmt.Errorf("detected incompatible versions:%v are incompatible with %v", v1Track, v2Track)
+		return "", &CompatibilityError{
+			Violations: map[string][]string{"v1": v1Track, "v2": v2Track},
+			message:    fmt.Sprintf("detected incompatible versions:%v are incompatible with %v", v1Track, v2Track),
+		}
 	}
 	if len(v1Track) > 0 {
 		return latestV1.Version, nil
@@ -334,6 +631,14 @@ func getLatestFromCompatibilityCheck(cfgs []util.VersionedConfig) (string, error
 	return "", fmt.Errorf("unable to find a valid API Schema version")
 }
 
+// EffectiveVersion returns the single apiVersion the given configs resolve to
+// after an upgrade, for use in logging and user-facing messages. Like
+// getLatestFromCompatibilityCheck, it errors when configs from the v1 and v2
+// schema tracks are mixed.
+func EffectiveVersion(cfgs []util.VersionedConfig) (string, error) {
+	return getLatestFromCompatibilityCheck(cfgs)
+}
+
 // IsCompatibleWith checks if the cfgs versions can be upgraded to toVersion.
 func IsCompatibleWith(cfgs []util.VersionedConfig, toVersion string) (bool, error) {
 	var pattern *regexp.Regexp
@@ -345,30 +650,63 @@ func IsCompatibleWith(cfgs []util.VersionedConfig, toVersion string) (bool, erro
 		return false, fmt.Errorf("target version %v is invalid", toVersion)
 	}
 	var badVersions []string
+	violations := map[string][]string{}
 	for _, cfg := range cfgs {
 		curVersion := cfg.GetVersion()
 		if matched := pattern.MatchString(curVersion); !matched {
 			badVersions = append(badVersions, curVersion)
+			violations[versionTrack(curVersion)] = append(violations[versionTrack(curVersion)], curVersion)
 		}
 	}
 	if len(badVersions) > 0 {
-		return false, fmt.Errorf(
-			"the following versions are incompatible with target version %v. upgrade aborted",
-			badVersions)
+		return false, &CompatibilityError{
+			Violations: violations,
+			message: fmt.Sprintf(
+				"the following versions are incompatible with target version %v. upgrade aborted",
+				badVersions),
+		}
 	}
 	return true, nil
 }
 
-// UpgradeTo upgrades the given configs to toVersion.
+// LatestTarget is a sentinel target for UpgradeTo meaning "whatever this
+// binary supports": it resolves per config to the newest version of the
+// source's schema track.
+const LatestTarget = "latest"
+
+// resolveTargetVersion maps the LatestTarget sentinel to the newest version
+// of the source's schema track: latestV1 for v1-track sources, latest for
+// v2-track sources. Explicit target versions pass through untouched.
+func resolveTargetVersion(fromVersion, toVersion string) (string, error) {
+	if toVersion != LatestTarget {
+		return toVersion, nil
+	}
+	switch versionTrack(fromVersion) {
+	case "v1":
+		return latestV1.Version, nil
+	case "v2":
+		return latest.Version, nil
+	}
+	return "", fmt.Errorf("unknown apiVersion %v", fromVersion)
+}
+
+// UpgradeTo upgrades the given configs to toVersion. The sentinel target
+// LatestTarget resolves to the newest version of each config's schema track.
 func UpgradeTo(configs []util.VersionedConfig, toVersion string) ([]util.VersionedConfig, error) {
 	upgradeNeeded := false
-	for _, cfg := range configs {
+	targets := make([]string, len(configs))
+	for i, cfg := range configs {
+		target, err := resolveTargetVersion(cfg.GetVersion(), toVersion)
+		if err != nil {
+			return nil, err
+		}
+		targets[i] = target
 		// Check that the config's version is not newer than the target version
 		currentVersion, err := apiversion.Parse(cfg.GetVersion())
 		if err != nil {
 			return nil, err
 		}
-		targetVersion, err := apiversion.Parse(toVersion)
+		targetVersion, err := apiversion.Parse(target)
 		if err != nil {
 			return nil, err
 		}
@@ -377,7 +715,7 @@ func UpgradeTo(configs []util.VersionedConfig, toVersion string) ([]util.Version
 			upgradeNeeded = true
 		}
 		if currentVersion.GT(targetVersion) {
-			return nil, fmt.Errorf("config version %q is more recent than target version %q: upgrade Skaffold", cfg.GetVersion(), toVersion)
+			return nil, fmt.Errorf("config version %q is more recent than target version %q: upgrade Skaffold", cfg.GetVersion(), target)
 		}
 	}
 	if !upgradeNeeded {
@@ -386,8 +724,8 @@ func UpgradeTo(configs []util.VersionedConfig, toVersion string) ([]util.Version
 	log.Entry(context.TODO()).Debugf("config version out of date: upgrading to latest %q", toVersion)
 	var err error
 	var upgraded []util.VersionedConfig
-	for _, cfg := range configs {
-		for cfg.GetVersion() != toVersion {
+	for i, cfg := range configs {
+		for cfg.GetVersion() != targets[i] {
 			cfg, err = cfg.Upgrade()
 			if err != nil {
 				return nil, fmt.Errorf("transforming skaffold config: %w", err)
@@ -397,3 +735,45 @@ func UpgradeTo(configs []util.VersionedConfig, toVersion string) ([]util.Version
 	}
 	return upgraded, nil
 }
+
+// ValidateAcrossVersions upgrades cfg one schema version at a time from fromVersion to
+// toVersion and validates the config at every hop, reporting the first version where
+// validation fails. It is meant for compatibility testing of upgrade chains.
+func ValidateAcrossVersions(cfg util.VersionedConfig, fromVersion string, toVersion string) error {
+	if cfg.GetVersion() != fromVersion {
+		return fmt.Errorf("config version %q does not match starting version %q", cfg.GetVersion(), fromVersion)
+	}
+	if err := validateAtVersion(cfg); err != nil {
+		return fmt.Errorf("config is invalid at version %q: %w", cfg.GetVersion(), err)
+	}
+	for cfg.GetVersion() != toVersion {
+		upgraded, err := cfg.Upgrade()
+		if err != nil {
+			return fmt.Errorf("upgrading config from %q: %w", cfg.GetVersion(), err)
+		}
+		if err := validateAtVersion(upgraded); err != nil {
+			return fmt.Errorf("config is invalid at version %q: %w", upgraded.GetVersion(), err)
+		}
+		cfg = upgraded
+	}
+	return nil
+}
+
+// validateAtVersion checks that the config still parses strictly against its own
+// schema version after being marshalled back to YAML.
+func validateAtVersion(cfg util.VersionedConfig) error {
+	factory, present := AllVersions.Find(cfg.GetVersion())
+	if !present {
+		return sErrors.ConfigUnknownAPIVersionErr(cfg.GetVersion())
+	}
+	buf, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to marshal config: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(factory()); err != nil && err != io.EOF {
+		return fmt.Errorf("unable to parse config: %w", err)
+	}
+	return nil
+}
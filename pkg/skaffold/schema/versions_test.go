@@ -17,7 +17,12 @@ limitations under the License.
 package schema
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -214,24 +219,77 @@ deploy:
 			isValid := IsSkaffoldConfig(tmpDir.Path("skaffold.yaml"))
 
 			t.CheckDeepEqual(test.isValid, isValid)
+			// the bytes variant detects the same contents without a file.
+			t.CheckDeepEqual(test.isValid, IsSkaffoldConfigBytes([]byte(test.contents)))
 		})
 	}
 }
 
-func TestParseConfigAndUpgrade(t *testing.T) {
+func TestDetectVersion(t *testing.T) {
 	tests := []struct {
-		apiVersion  []string
 		description string
-		config      []string
-		expected    []util.VersionedConfig
+		contents    string
+		expected    string
 		shouldErr   bool
+	}{
+		{
+			description: "current version",
+			contents:    fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, minimalConfig),
+			expected:    latest.Version,
+		},
+		{
+			description: "old version",
+			contents:    "apiVersion: skaffold/v1beta6\nkind: Config\n",
+			expected:    "skaffold/v1beta6",
+		},
+		{
+			description: "invalid fields elsewhere are tolerated",
+			contents:    fmt.Sprintf("apiVersion: %s\nkind: Config\nbuild: 42\n", latest.Version),
+			expected:    latest.Version,
+		},
+		{
+			description: "unknown version is still reported",
+			contents:    "apiVersion: skaffold/v1beta100\nkind: Config\n",
+			expected:    "skaffold/v1beta100",
+		},
+		{
+			description: "not a skaffold config",
+			contents:    "apiVersion: v1\nkind: Pod\n",
+			shouldErr:   true,
+		},
+		{
+			description: "not yaml at all",
+			contents:    "{{",
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			tmpDir := t.NewTempDir().
+				Write("skaffold.yaml", test.contents)
+
+			version, err := DetectVersion(tmpDir.Path("skaffold.yaml"))
+
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, version)
+		})
+	}
+}
+
+func TestParseConfigAndUpgrade(t *testing.T) {
+	tests := []struct {
+		apiVersion      []string
+		description     string
+		config          []string
+		expected        []util.VersionedConfig
+		shouldErr       bool
+		expectedErrPart string
 	}{
 		{
 			apiVersion:  []string{latest.Version},
 			description: "Kaniko Volume Mount - ConfigMap",
 			config:      []string{kanikoConfigMap},
 			expected: []util.VersionedConfig{config(
-				withClusterBuild("some-secret", "/secret", "default", "", "20m",
+				withClusterBuild("some-secret", "/secret", "default", "", "20m0s",
 					withGitTagger(),
 					withKanikoArtifact(),
 					withKanikoVolumeMount("docker-config", "/kaniko/.docker"),
@@ -326,7 +384,7 @@ func TestParseConfigAndUpgrade(t *testing.T) {
 				withRawK8s("dep.yaml", "svc.yaml"),
 				withLogsPrefix("container"),
 			), config(
-				withClusterBuild("secret-name", "/secret", "nskaniko", "/secret.json", "120m",
+				withClusterBuild("secret-name", "/secret", "nskaniko", "/secret.json", "2h0m0s",
 					withGitTagger(),
 					withDockerConfig("config-name", "/kaniko/.docker"),
 					withKanikoArtifact(),
@@ -347,7 +405,7 @@ func TestParseConfigAndUpgrade(t *testing.T) {
 				withRawK8s("dep.yaml", "svc.yaml"),
 				withLogsPrefix("container"),
 			), config(
-				withClusterBuild("secret-name", "/secret", "nskaniko", "/secret.json", "120m",
+				withClusterBuild("secret-name", "/secret", "nskaniko", "/secret.json", "2h0m0s",
 					withGitTagger(),
 					withDockerConfig("config-name", "/kaniko/.docker"),
 					withKanikoArtifact(),
@@ -360,7 +418,7 @@ func TestParseConfigAndUpgrade(t *testing.T) {
 			description: "Minimal Cluster config",
 			config:      []string{minimalClusterConfig},
 			expected: []util.VersionedConfig{config(
-				withClusterBuild("", "/secret", "default", "", "20m",
+				withClusterBuild("", "/secret", "default", "", "20m0s",
 					withGitTagger(),
 					withKanikoArtifact(),
 				),
@@ -372,7 +430,7 @@ func TestParseConfigAndUpgrade(t *testing.T) {
 			description: "Complete Cluster config",
 			config:      []string{completeClusterConfig},
 			expected: []util.VersionedConfig{config(
-				withClusterBuild("secret-name", "/secret", "nskaniko", "/secret.json", "120m",
+				withClusterBuild("secret-name", "/secret", "nskaniko", "/secret.json", "2h0m0s",
 					withGitTagger(),
 					withDockerConfig("config-name", "/kaniko/.docker"),
 					withKanikoArtifact(),
@@ -399,10 +457,11 @@ func TestParseConfigAndUpgrade(t *testing.T) {
 			shouldErr:   true,
 		},
 		{
-			apiVersion:  []string{latest.Version},
-			description: "invalid statusCheckDeadline",
-			config:      []string{invalidStatusCheckConfig},
-			shouldErr:   true,
+			apiVersion:      []string{latest.Version},
+			description:     "invalid statusCheckDeadline",
+			config:          []string{invalidStatusCheckConfig},
+			shouldErr:       true,
+			expectedErrPart: "line",
 		},
 		{
 			apiVersion:  []string{latest.Version},
@@ -435,13 +494,16 @@ func TestParseConfigAndUpgrade(t *testing.T) {
 			tmpDir := t.NewTempDir().
 				Write("skaffold.yaml", format(t, test.config, test.apiVersion))
 
-			cfgs, err := ParseConfigAndUpgrade(tmpDir.Path("skaffold.yaml"))
+			cfgs, _, err := ParseConfigAndUpgrade(tmpDir.Path("skaffold.yaml"))
 			for _, cfg := range cfgs {
 				err := defaults.Set(cfg.(*latest.SkaffoldConfig))
 				t.CheckNoError(err)
 			}
 
 			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, cfgs)
+			if test.expectedErrPart != "" {
+				t.CheckErrorContains(test.expectedErrPart, err)
+			}
 		})
 	}
 }
@@ -455,7 +517,7 @@ func TestMarshalConfig(t *testing.T) {
 		{
 			description: "Kaniko Volume Mount - ConfigMap",
 			config: config(
-				withClusterBuild("some-secret", "/some/secret", "default", "", "20m",
+				withClusterBuild("some-secret", "/some/secret", "default", "", "20m0s",
 					withGitTagger(),
 					withKanikoArtifact(),
 					withKanikoVolumeMount("docker-config", "/kaniko/.docker"),
@@ -724,6 +786,69 @@ func TestUpgradeToNextVersion(t *testing.T) {
 	}
 }
 
+func TestValidateAcrossVersions(t *testing.T) {
+	tests := []struct {
+		description string
+		fromVersion string
+		shouldErr   bool
+	}{
+		{
+			description: "valid config upgrades and validates at every hop",
+			fromVersion: v2beta8.Version,
+		},
+		{
+			description: "starting version mismatch",
+			fromVersion: v2beta1.Version,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			cfg := v2beta8.NewSkaffoldConfig()
+			err := yaml.Unmarshal([]byte(fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", v2beta8.Version, simpleConfig)), cfg)
+			t.CheckNoError(err)
+
+			err = ValidateAcrossVersions(cfg, test.fromVersion, latestV1.Version)
+
+			t.CheckError(test.shouldErr, err)
+		})
+	}
+}
+
+func TestDeprecationWarnings(t *testing.T) {
+	testutil.Run(t, "deprecated source version warns", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", fmt.Sprintf("apiVersion: skaffold/v1beta6\nkind: Config\n%s", simpleConfig))
+
+		cfgs, deprecations, err := ParseConfigAndUpgrade(tmpDir.Path("skaffold.yaml"))
+
+		t.CheckNoError(err)
+		// the config stays valid and usable; the warning is informational.
+		t.CheckDeepEqual(latest.Version, cfgs[0].GetVersion())
+		t.CheckDeepEqual(1, len(deprecations))
+		t.CheckContains(`apiVersion "skaffold/v1beta6" is deprecated`, deprecations[0])
+	})
+	testutil.Run(t, "repeated deprecated version warns once", func(t *testutil.T) {
+		doc := fmt.Sprintf("apiVersion: skaffold/v1beta6\nkind: Config\n%s", simpleConfig)
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", doc+"---\n"+doc)
+
+		_, deprecations, err := ParseConfigAndUpgrade(tmpDir.Path("skaffold.yaml"))
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(1, len(deprecations))
+	})
+	testutil.Run(t, "current version does not warn", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, simpleConfig))
+
+		_, deprecations, err := ParseConfigAndUpgrade(tmpDir.Path("skaffold.yaml"))
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(0, len(deprecations))
+	})
+}
+
 func TestParseConfigAndUpgradeToOlderVersion(t *testing.T) {
 	testutil.Run(t, "", func(t *testutil.T) {
 		t.NewTempDir().
@@ -737,6 +862,166 @@ func TestParseConfigAndUpgradeToOlderVersion(t *testing.T) {
 	})
 }
 
+func TestParseConfigFromReader(t *testing.T) {
+	testutil.Run(t, "multi-document stream", func(t *testutil.T) {
+		doc := fmt.Sprintf("apiVersion: %s\nkind: Config\n", latest.Version)
+		cfgs, err := ParseConfigFromReader(strings.NewReader(doc + "---\n" + doc))
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(2, len(cfgs))
+		t.CheckDeepEqual(latest.Version, cfgs[0].GetVersion())
+	})
+
+	testutil.Run(t, "missing apiVersion", func(t *testutil.T) {
+		_, err := ParseConfigFromReader(strings.NewReader("kind: Config\n"))
+
+		t.CheckErrorContains("missing apiVersion", err)
+	})
+}
+
+func TestParseConfigFromURL(t *testing.T) {
+	doc := fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, minimalConfig)
+
+	testutil.Run(t, "valid config over HTTP", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, doc)
+		}))
+		t.Cleanup(ts.Close)
+
+		cfgs, err := ParseConfigFromURL(context.Background(), ts.URL)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(1, len(cfgs))
+		t.CheckDeepEqual(latest.Version, cfgs[0].GetVersion())
+	})
+	testutil.Run(t, "redirects are followed", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/final" {
+				http.Redirect(w, r, "/final", http.StatusFound)
+				return
+			}
+			io.WriteString(w, doc)
+		}))
+		t.Cleanup(ts.Close)
+
+		cfgs, err := ParseConfigFromURL(context.Background(), ts.URL)
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(1, len(cfgs))
+	})
+	testutil.Run(t, "non-200 status is a fetch error", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "gone", http.StatusNotFound)
+		}))
+		t.Cleanup(ts.Close)
+
+		_, err := ParseConfigFromURL(context.Background(), ts.URL)
+
+		t.CheckErrorContains("fetching skaffold config from", err)
+	})
+	testutil.Run(t, "unreachable server is a fetch error", func(t *testutil.T) {
+		_, err := ParseConfigFromURL(context.Background(), "http://127.0.0.1:1/skaffold.yaml")
+
+		t.CheckErrorContains("fetching skaffold config from", err)
+	})
+	testutil.Run(t, "invalid body is a parse error", func(t *testutil.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "not a config")
+		}))
+		t.Cleanup(ts.Close)
+
+		_, err := ParseConfigFromURL(context.Background(), ts.URL)
+
+		t.CheckErrorContains("missing apiVersion", err)
+	})
+	testutil.Run(t, "oversized response is rejected", func(t *testutil.T) {
+		t.Override(&maxRemoteConfigSize, int64(16))
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, doc)
+		}))
+		t.Cleanup(ts.Close)
+
+		_, err := ParseConfigFromURL(context.Background(), ts.URL)
+
+		t.CheckErrorContains("exceeds 16 bytes", err)
+	})
+}
+
+func TestParseConfigByName(t *testing.T) {
+	doc := func(name string) string {
+		return fmt.Sprintf("apiVersion: %s\nkind: Config\nmetadata:\n  name: %s\n%s", latest.Version, name, simpleConfig)
+	}
+
+	testutil.Run(t, "named document is selected", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", doc("app")+"---\n"+doc("infra"))
+
+		cfg, err := ParseConfigByName(tmpDir.Path("skaffold.yaml"), "infra")
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual("infra", cfg.(*latest.SkaffoldConfig).Metadata.Name)
+	})
+	testutil.Run(t, "unknown name lists the available ones", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", doc("app")+"---\n"+doc("infra"))
+
+		_, err := ParseConfigByName(tmpDir.Path("skaffold.yaml"), "missing")
+
+		t.CheckErrorContains(`no config named "missing"`, err)
+		t.CheckErrorContains("[app infra]", err)
+	})
+	testutil.Run(t, "duplicate names are rejected", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", doc("app")+"---\n"+doc("app"))
+
+		_, err := ParseConfigByName(tmpDir.Path("skaffold.yaml"), "app")
+
+		t.CheckErrorContains(`multiple configs named "app"`, err)
+	})
+}
+
+func TestParseConfigWithBOM(t *testing.T) {
+	testutil.Run(t, "leading byte-order mark is stripped", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("skaffold.yaml", "\xef\xbb\xbf"+fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, minimalConfig))
+
+		cfgs, err := ParseConfig(tmpDir.Path("skaffold.yaml"))
+
+		t.CheckNoError(err)
+		t.CheckDeepEqual(1, len(cfgs))
+		t.CheckDeepEqual(latest.Version, cfgs[0].GetVersion())
+	})
+}
+
+func TestParseConfigWithMergeKeys(t *testing.T) {
+	testutil.Run(t, "anchored artifact merged into another", func(t *testutil.T) {
+		doc := fmt.Sprintf(`apiVersion: %s
+kind: Config
+build:
+  artifacts:
+  - &base
+    image: example
+    context: app
+    docker:
+      dockerfile: Dockerfile.dev
+  - <<: *base
+    image: example2
+`, latest.Version)
+
+		cfgs, err := ParseConfigFromReader(strings.NewReader(doc))
+
+		t.CheckNoError(err)
+		artifacts := cfgs[0].(*latest.SkaffoldConfig).Build.Artifacts
+		t.CheckDeepEqual(2, len(artifacts))
+		t.CheckDeepEqual("example", artifacts[0].ImageName)
+		t.CheckDeepEqual("example2", artifacts[1].ImageName)
+		// the merged artifact carries every field of the anchored one except
+		// the image it overrides.
+		t.CheckDeepEqual("app", artifacts[1].Workspace)
+		t.CheckDeepEqual("Dockerfile.dev", artifacts[1].DockerArtifact.DockerfilePath)
+	})
+}
+
 func TestGetLatestFromCompatibilityCheck(t *testing.T) {
 	tests := []struct {
 		description string
@@ -773,7 +1058,7 @@ func TestGetLatestFromCompatibilityCheck(t *testing.T) {
 			description: "invalid compatibility among v1 and v2 versions",
 			shouldErr:   true,
 			err: fmt.Errorf("detected incompatible versions:%v are incompatible with %v",
-				[]string{latestV1.Version, v1alpha1.Version}, []string{latest.Version}),
+				[]string{v1alpha1.Version}, []string{latest.Version}),
 		},
 		{
 			apiVersions: []util.VersionedConfig{
@@ -781,13 +1066,49 @@ func TestGetLatestFromCompatibilityCheck(t *testing.T) {
 			},
 			description: "invalid api version",
 			shouldErr:   true,
-			err:         fmt.Errorf("unknown apiVersion vXalpaY"),
+			// the error must echo the input apiVersion verbatim.
+			err: fmt.Errorf("unknown apiVersion vXalphaY"),
 		},
 	}
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
 			upToDateVersion, err := getLatestFromCompatibilityCheck(test.apiVersions)
 			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, upToDateVersion)
+			if test.err != nil {
+				t.CheckErrorContains(test.err.Error(), err)
+			}
+		})
+	}
+}
+
+func TestEffectiveVersion(t *testing.T) {
+	tests := []struct {
+		description string
+		apiVersions []util.VersionedConfig
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "all v1 configs resolve to the latest v1 version",
+			apiVersions: []util.VersionedConfig{
+				&v1alpha1.SkaffoldConfig{APIVersion: v1alpha1.Version},
+				&v2beta1.SkaffoldConfig{APIVersion: v2beta1.Version},
+			},
+			expected: latestV1.Version,
+		},
+		{
+			description: "mixed v1 and v2 configs are rejected",
+			apiVersions: []util.VersionedConfig{
+				&v1alpha1.SkaffoldConfig{APIVersion: v1alpha1.Version},
+				&latest.SkaffoldConfig{APIVersion: latest.Version},
+			},
+			shouldErr: true,
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			version, err := EffectiveVersion(test.apiVersions)
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, version)
 		})
 	}
 }
@@ -848,3 +1169,115 @@ func TestIsCompatibleWith(t *testing.T) {
 		})
 	}
 }
+
+func TestCompatibilityErrorViolations(t *testing.T) {
+	testutil.Run(t, "mixed v1 and v2 configs list every offender by track", func(t *testutil.T) {
+		_, err := getLatestFromCompatibilityCheck([]util.VersionedConfig{
+			&v1alpha1.SkaffoldConfig{APIVersion: v1alpha1.Version},
+			&v1beta1.SkaffoldConfig{APIVersion: v1beta1.Version},
+			&latest.SkaffoldConfig{APIVersion: latest.Version},
+		})
+
+		var compatErr *CompatibilityError
+		if !errors.As(err, &compatErr) {
+			t.Fatalf("expected a *CompatibilityError, got %v", err)
+		}
+		t.CheckDeepEqual(map[string][]string{
+			"v1": {v1alpha1.Version, v1beta1.Version},
+			"v2": {latest.Version},
+		}, compatErr.Violations)
+	})
+	testutil.Run(t, "incompatible upgrade target lists every offender", func(t *testutil.T) {
+		_, err := IsCompatibleWith([]util.VersionedConfig{
+			&v1alpha1.SkaffoldConfig{APIVersion: v1alpha1.Version},
+			&v1beta1.SkaffoldConfig{APIVersion: v1beta1.Version},
+		}, latest.Version)
+
+		var compatErr *CompatibilityError
+		if !errors.As(err, &compatErr) {
+			t.Fatalf("expected a *CompatibilityError, got %v", err)
+		}
+		t.CheckDeepEqual(map[string][]string{
+			"v1": {v1alpha1.Version, v1beta1.Version},
+		}, compatErr.Violations)
+	})
+}
+
+func TestSupportedVersions(t *testing.T) {
+	versions := SupportedVersions()
+
+	testutil.CheckDeepEqual(t, len(AllVersions), len(versions))
+	testutil.CheckDeepEqual(t, v1alpha1.Version, versions[0])
+	testutil.CheckDeepEqual(t, LatestVersion(), versions[len(versions)-1])
+
+	for _, version := range versions {
+		_, present := AllVersions.Find(version)
+		testutil.CheckDeepEqual(t, true, present)
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	testutil.CheckDeepEqual(t, latest.Version, LatestVersion())
+}
+
+func TestAssertSupported(t *testing.T) {
+	testutil.Run(t, "latest version is supported", func(t *testutil.T) {
+		t.CheckNoError(AssertSupported(latest.Version, "v2.0.0"))
+	})
+	testutil.Run(t, "older version is supported", func(t *testutil.T) {
+		t.CheckNoError(AssertSupported("skaffold/v1alpha1", "v2.0.0"))
+	})
+	testutil.Run(t, "newer version errors with upgrade guidance", func(t *testutil.T) {
+		err := AssertSupported("skaffold/v9", "v2.0.0")
+		t.CheckErrorContains(`config version "skaffold/v9" is more recent than the latest version`, err)
+		t.CheckErrorContains("upgrade Skaffold", err)
+	})
+	testutil.Run(t, "unparseable version errors", func(t *testutil.T) {
+		t.CheckErrorContains("invalid api version", AssertSupported("not-a-version", "v2.0.0"))
+	})
+}
+
+// mutatingConfig is a fake VersionedConfig whose Upgrade mutates its
+// receiver, proving AssertUpgradeIdempotent can actually fail.
+type mutatingConfig struct {
+	APIVersion string `yaml:"apiVersion"`
+	Value      string `yaml:"value"`
+}
+
+func (c *mutatingConfig) GetVersion() string { return c.APIVersion }
+
+func (c *mutatingConfig) Upgrade() (util.VersionedConfig, error) {
+	c.Value = "mutated"
+	return &mutatingConfig{APIVersion: latest.Version, Value: c.Value}, nil
+}
+
+func TestAssertUpgradeIdempotent(t *testing.T) {
+	testutil.Run(t, "latest config refuses to upgrade", func(t *testutil.T) {
+		cfg := latest.NewSkaffoldConfig()
+		err := yaml.Unmarshal([]byte(fmt.Sprintf("apiVersion: %s\nkind: Config\n%s", latest.Version, simpleConfig)), cfg)
+		t.CheckNoError(err)
+
+		t.CheckNoError(AssertUpgradeIdempotent(cfg))
+	})
+	testutil.Run(t, "older config upgrades without mutating its source", func(t *testutil.T) {
+		cfg := v1alpha1.NewSkaffoldConfig()
+		err := yaml.Unmarshal([]byte("apiVersion: skaffold/v1alpha1\nkind: Config\n"), cfg)
+		t.CheckNoError(err)
+
+		t.CheckNoError(AssertUpgradeIdempotent(cfg))
+	})
+	testutil.Run(t, "an upgrade mutating its receiver is caught", func(t *testutil.T) {
+		cfg := &mutatingConfig{APIVersion: "skaffold/v1alpha1", Value: "original"}
+
+		err := AssertUpgradeIdempotent(cfg)
+
+		t.CheckErrorContains("was mutated by its upgrade", err)
+	})
+	testutil.Run(t, "a latest config silently upgrading is caught", func(t *testutil.T) {
+		cfg := &mutatingConfig{APIVersion: LatestVersion(), Value: "original"}
+
+		err := AssertUpgradeIdempotent(cfg)
+
+		t.CheckErrorContains("must refuse to upgrade", err)
+	})
+}
@@ -166,7 +166,7 @@ func (s *Runner) selectSurvey(takenSurveys map[string]struct{}) string {
 		return ""
 	}
 	sortSurveys(candidates)
-	cfgs, err := parseConfig(s.skaffoldConfig)
+	cfgs, _, err := parseConfig(s.skaffoldConfig)
 	if err != nil {
 		log.Entry(context.TODO()).Debugf("error parsing skaffold.yaml %s", err)
 		return ""
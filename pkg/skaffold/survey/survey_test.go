@@ -143,8 +143,8 @@ func TestShouldDisplayPrompt(t *testing.T) {
 		testutil.Run(t, test.description, func(t *testutil.T) {
 			t.Override(&surveys, []config{hats})
 			t.Override(&sConfig.ReadConfigFile, func(string) (*sConfig.GlobalConfig, error) { return test.cfg, nil })
-			t.Override(&parseConfig, func(string) ([]schemaUtil.VersionedConfig, error) {
-				return []schemaUtil.VersionedConfig{mockVersionedConfig{version: "test"}}, nil
+			t.Override(&parseConfig, func(string) ([]schemaUtil.VersionedConfig, []string, error) {
+				return []schemaUtil.VersionedConfig{mockVersionedConfig{version: "test"}}, nil, nil
 			})
 			_, actual := New("test", "yaml", "dev").shouldDisplaySurveyPrompt()
 			t.CheckDeepEqual(test.expected, actual)
@@ -286,8 +286,8 @@ func TestRecentlyPromptedOrTaken(t *testing.T) {
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
 			t.Override(&surveys, test.input)
-			t.Override(&parseConfig, func(string) ([]schemaUtil.VersionedConfig, error) {
-				return []schemaUtil.VersionedConfig{mockVersionedConfig{version: "test"}}, nil
+			t.Override(&parseConfig, func(string) ([]schemaUtil.VersionedConfig, []string, error) {
+				return []schemaUtil.VersionedConfig{mockVersionedConfig{version: "test"}}, nil, nil
 			})
 			actual := New("dummy", "yaml", "cmd").recentlyPromptedOrTaken(test.cfg)
 			t.CheckDeepEqual(test.expected, actual)
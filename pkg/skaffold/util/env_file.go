@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envFileKeyPattern matches valid variable names in a dotenv file.
+var envFileKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ParseEnvFile reads a dotenv file and returns the variables it defines.
+// Blank lines and lines starting with `#` are ignored; every other line must be
+// of the form KEY=VALUE. Malformed lines are reported with their line number.
+func ParseEnvFile(path string) (map[string]string, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+
+	env := make(map[string]string)
+	for i, line := range strings.Split(string(buf), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%s:%d: invalid line %q: expected KEY=VALUE", path, i+1, line)
+		}
+		key = strings.TrimSpace(key)
+		if !envFileKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%s:%d: invalid variable name %q", path, i+1, key)
+		}
+		env[key] = strings.TrimSpace(value)
+	}
+	return env, nil
+}
+
+// ValidateEnvFile checks that a referenced dotenv file exists and parses.
+func ValidateEnvFile(path string) error {
+	_, err := ParseEnvFile(path)
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	tests := []struct {
+		description string
+		content     string
+		expected    map[string]string
+		shouldErr   bool
+		errContains string
+	}{
+		{
+			description: "valid file with comments and blank lines",
+			content:     "# database settings\nDB_HOST=localhost\n\nDB_PORT=5432\nEMPTY=\n",
+			expected: map[string]string{
+				"DB_HOST": "localhost",
+				"DB_PORT": "5432",
+				"EMPTY":   "",
+			},
+		},
+		{
+			description: "line without an equals sign reports its line number",
+			content:     "DB_HOST=localhost\nnot a variable\n",
+			shouldErr:   true,
+			errContains: ":2: invalid line",
+		},
+		{
+			description: "invalid variable name reports its line number",
+			content:     "DB_HOST=localhost\nDB_PORT=5432\n9BAD=value\n",
+			shouldErr:   true,
+			errContains: ":3: invalid variable name",
+		},
+	}
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			path := t.NewTempDir().Write(".env", test.content).Path(".env")
+
+			env, err := ParseEnvFile(path)
+
+			t.CheckError(test.shouldErr, err)
+			if test.shouldErr {
+				t.CheckErrorContains(test.errContains, err)
+			} else {
+				t.CheckDeepEqual(test.expected, env)
+			}
+		})
+	}
+}
+
+func TestValidateEnvFile(t *testing.T) {
+	testutil.Run(t, "missing file", func(t *testutil.T) {
+		err := ValidateEnvFile("does-not-exist.env")
+
+		t.CheckErrorContains("reading env file", err)
+	})
+}